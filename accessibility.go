@@ -0,0 +1,170 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// --- Accessibility Palettes ---
+
+// Palette is a color scheme applied on top of the asset pack's colors
+type Palette string
+
+const (
+	PaletteStandard     Palette = "standard"     // The pack's own colors
+	PaletteDeuteranopia Palette = "deuteranopia" // Green-weak
+	PaletteProtanopia   Palette = "protanopia"   // Red-weak
+	PaletteTritanopia   Palette = "tritanopia"   // Blue-weak
+)
+
+// palettes lists every palette in the order the settings screen cycles through them
+var palettes = []Palette{PaletteStandard, PaletteDeuteranopia, PaletteProtanopia, PaletteTritanopia}
+
+// paletteNames are the message keys of the names shown on the settings screen
+var paletteNames = map[Palette]string{
+	PaletteStandard:     "palette.standard",
+	PaletteDeuteranopia: "palette.deuteranopia",
+	PaletteProtanopia:   "palette.protanopia",
+	PaletteTritanopia:   "palette.tritanopia",
+}
+
+// paletteColors are each palette's overrides, keyed like a pack manifest's
+// colors. Kelp, coins and jellyfish are kept apart by lightness as well as
+// hue, so they stay distinct even where two hues look alike.
+var paletteColors = map[Palette]map[string]string{
+	// Red and green look alike: blue kelp, yellow-orange coins and dark purple
+	// jellyfish on pale water, with an orange leader ring
+	PaletteDeuteranopia: {
+		"water":         "#cfe6f5",
+		"kelpDark":      "#08306b",
+		"kelpMedium":    "#0b559f",
+		"kelpLight":     "#2b7bba",
+		"kelpAccent":    "#1c4f8a",
+		"coinBorder":    "#8c5000",
+		"coinMain":      "#ffc20a",
+		"coinHighlight": "#fff0a0",
+		"coinShadow":    "#d99a00",
+		"jellyBell":     "#5b2a86c8",
+		"jellyRim":      "#8f5fc0e6",
+		"jellyTentacle": "#7a4aa8a0",
+		"leaderMarker":  "#e66100",
+	},
+	// Like deuteranopia, but reds also look dark, so nothing relies on red
+	PaletteProtanopia: {
+		"water":         "#d4e9f7",
+		"kelpDark":      "#002b5c",
+		"kelpMedium":    "#004c99",
+		"kelpLight":     "#3380cc",
+		"kelpAccent":    "#1a4d80",
+		"coinBorder":    "#6b5300",
+		"coinMain":      "#ffd700",
+		"coinHighlight": "#fff5a0",
+		"coinShadow":    "#c9a800",
+		"jellyBell":     "#332288c8",
+		"jellyRim":      "#6a5acde6",
+		"jellyTentacle": "#4b3ca8a0",
+		"leaderMarker":  "#ffb000",
+	},
+	// Blue and yellow (and blue and green) look alike: red kelp, teal coins and
+	// grey jellyfish on near-white water, with a magenta leader ring
+	PaletteTritanopia: {
+		"water":         "#e6f2f2",
+		"kelpDark":      "#67000d",
+		"kelpMedium":    "#a50f15",
+		"kelpLight":     "#d7301f",
+		"kelpAccent":    "#7f1d1d",
+		"coinBorder":    "#0f5257",
+		"coinMain":      "#2ec4b6",
+		"coinHighlight": "#a8f0e8",
+		"coinShadow":    "#1f9e94",
+		"jellyBell":     "#333333c8",
+		"jellyRim":      "#666666e6",
+		"jellyTentacle": "#4d4d4da0",
+		"leaderMarker":  "#e0218a",
+	},
+}
+
+// highContrastColors darken the water and scenery and fade the bubbles, so
+// the school, hazards and coins stand out; hazards and coins are also outlined
+var highContrastColors = map[string]string{
+	"water":        "#000814",
+	"rockTop":      "#1a1f29",
+	"rockBottom":   "#0f131a",
+	"midWater":     "#141a24",
+	"seaweedDark":  "#0d1a12",
+	"seaweedLight": "#16261c",
+	"bubbleOuter":  "#ffffff18",
+	"bubbleInner":  "#ffffff30",
+	"outline":      "#ffffff",
+}
+
+// Collision zone colors
+var (
+	zoneHazardColor = color.RGBA{255, 90, 0, 90}     // Kelp and jellyfish fill
+	zoneSchoolColor = color.RGBA{255, 255, 255, 200} // School hitbox outline
+)
+
+// hazardOutlineWidth is the stroke width of high contrast outlines
+const hazardOutlineWidth = 3
+
+// sceneColors returns the pack's colors with the palette and high contrast settings applied
+func sceneColors(pack *AssetPack, s *Settings) PackColors {
+	colors := pack.Colors
+	if err := colors.override(paletteColors[s.Palette]); err != nil {
+		log.Printf("Palette %s: %v", s.Palette, err)
+	}
+	if s.HighContrast {
+		if err := colors.override(highContrastColors); err != nil {
+			log.Printf("High contrast palette: %v", err)
+		}
+	}
+	return colors
+}
+
+// showLeaderMarker reports whether the leader gets a ring, so it isn't told
+// apart from the followers by shade alone
+func (s *Settings) showLeaderMarker() bool {
+	return s.Palette != PaletteStandard || s.HighContrast
+}
+
+// paletteKelpAtlases caches kelp generated in palettes other than the pack's own
+var paletteKelpAtlases = map[PackColors]*SpriteAtlas{}
+
+// kelpAtlasFor returns the pack's kelp in the given colors. Kelp from a pack
+// image can't be recolored and is returned as is.
+func kelpAtlasFor(pack *AssetPack, colors PackColors) *SpriteAtlas {
+	if !pack.kelpGenerated || colors == pack.Colors {
+		return pack.kelpAtlas
+	}
+	if atlas, ok := paletteKelpAtlases[colors]; ok {
+		return atlas
+	}
+	atlas := createKelpAtlas(&colors)
+	paletteKelpAtlases[colors] = atlas
+	return atlas
+}
+
+// --- Collision Zones ---
+
+// drawCollisionZones shades the area of every hazard that ends a run and
+// outlines the school's hitboxes, in the active collision mode
+func (g *Game) drawCollisionZones(screen *ebiten.Image) {
+	for _, obs := range g.obstacles {
+		x := g.interpolate(obs.prevX, obs.x)
+		vector.FillRect(screen, float32(x), float32(obs.y), float32(obs.width), float32(obs.height), zoneHazardColor, false)
+	}
+	for _, jelly := range g.jellyfish {
+		c := jelly.collisionCircle()
+		dx, dy := g.interpolate(jelly.prevX, jelly.x)-jelly.x, g.interpolate(jelly.prevY, jelly.y)-jelly.y
+		vector.FillCircle(screen, float32(c.x+dx), float32(c.y+dy), float32(c.radius), zoneHazardColor, true)
+	}
+
+	leaderY := g.interpolate(g.prevPlayerY, g.playerY)
+	g.strokeHitbox(screen, PlayerX, leaderY, PlayerSize, leaderCollisionRadius, zoneSchoolColor)
+	for _, fish := range g.fish {
+		g.strokeHitbox(screen, g.interpolate(fish.prevX, fish.x), g.interpolate(fish.prevY, fish.y), FishSize, followerCollisionRadius, zoneSchoolColor)
+	}
+}
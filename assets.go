@@ -0,0 +1,125 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // Register the PNG decoder for image.Decode
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Asset Loading ---
+
+// embeddedAssets holds the default assets compiled into the binary, so the
+// game runs from any working directory
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+// assetOverrideDir is an optional directory (set with --assets) whose files
+// replace the embedded assets of the same name, for modding
+var assetOverrideDir string
+
+// readAsset returns the contents of an asset, preferring the override
+// directory and falling back to the embedded copy. Names are slash-separated
+// and relative to the assets directory (e.g. "fish.png").
+func readAsset(name string) ([]byte, error) {
+	f, err := openAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// loadRawImageAsset decodes an image asset without uploading it to the GPU,
+// for sprites whose pixels are processed at load time
+func loadRawImageAsset(name string) (image.Image, error) {
+	f, err := openAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return img, nil
+}
+
+// loadImageAsset loads an image asset as an ebiten image
+func loadImageAsset(name string) (*ebiten.Image, error) {
+	img, err := loadRawImageAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// openAsset opens an asset for streaming, with the same lookup order as readAsset
+func openAsset(name string) (fs.File, error) {
+	if assetOverrideDir != "" {
+		f, err := os.Open(filepath.Join(assetOverrideDir, filepath.FromSlash(name)))
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return embeddedAssets.Open("assets/" + name)
+}
+
+// --- Fallback Sprites ---
+
+// createFallbackFishImage creates a simple right-facing fish used when the
+// fish sprite can't be loaded, so a bad asset never stops the game
+func createFallbackFishImage() image.Image {
+	width := 128
+	height := 80
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	body := color.RGBA{255, 140, 40, 255}   // Orange
+	belly := color.RGBA{255, 200, 120, 255} // Pale orange
+	eye := color.RGBA{20, 20, 20, 255}
+
+	// Body is an ellipse on the right, the tail a triangle on the left
+	bodyCX, bodyCY := 76.0, 40.0
+	bodyRX, bodyRY := 48.0, 28.0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x)+0.5, float64(y)+0.5
+			dx := (fx - bodyCX) / bodyRX
+			dy := (fy - bodyCY) / bodyRY
+			if dx*dx+dy*dy <= 1 {
+				if fy > bodyCY+bodyRY*0.3 {
+					setPixel(img, x, y, belly)
+				} else {
+					setPixel(img, x, y, body)
+				}
+				continue
+			}
+			// Tail widens toward the left edge
+			if fx < 36 && math.Abs(fy-bodyCY) < (36-fx)*0.9 {
+				setPixel(img, x, y, body)
+			}
+		}
+	}
+
+	// Eye near the head
+	for y := 30; y < 36; y++ {
+		for x := 100; x < 106; x++ {
+			setPixel(img, x, y, eye)
+		}
+	}
+
+	return img
+}
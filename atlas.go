@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Sprite Atlas ---
+
+// atlasFrame is the JSON description of one frame's rectangle in the atlas image
+type atlasFrame struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// atlasAnimation is the JSON description of a named frame sequence
+type atlasAnimation struct {
+	Frames []string `json:"frames"` // Frame names, in playback order
+	FPS    float64  `json:"fps"`    // Playback rate in frames per second
+	Loop   bool     `json:"loop"`   // Whether to wrap back to the first frame
+}
+
+// atlasMeta is the JSON frame metadata that accompanies an atlas image
+type atlasMeta struct {
+	Frames     map[string]atlasFrame     `json:"frames"`
+	Animations map[string]atlasAnimation `json:"animations"`
+}
+
+// SpriteAtlas is a single image holding many named frames and the animations built from them
+type SpriteAtlas struct {
+	image      *ebiten.Image
+	source     image.Image // CPU copy of image for tracing collision masks (nil if not kept)
+	frames     map[string]*ebiten.Image
+	animations map[string]*Animation
+}
+
+// LoadSpriteAtlas loads an atlas image and its JSON frame metadata from the assets
+func LoadSpriteAtlas(imageName, metaName string) (*SpriteAtlas, error) {
+	img, err := loadRawImageAsset(imageName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readAsset(metaName)
+	if err != nil {
+		return nil, err
+	}
+	var meta atlasMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", metaName, err)
+	}
+	atlas, err := newSpriteAtlas(ebiten.NewImageFromImage(img), meta)
+	if err != nil {
+		return nil, err
+	}
+	atlas.source = img
+	return atlas, nil
+}
+
+// newSpriteAtlas slices the atlas image into frames and resolves each animation's frame names
+func newSpriteAtlas(img *ebiten.Image, meta atlasMeta) (*SpriteAtlas, error) {
+	atlas := &SpriteAtlas{
+		image:      img,
+		frames:     make(map[string]*ebiten.Image, len(meta.Frames)),
+		animations: make(map[string]*Animation, len(meta.Animations)),
+	}
+
+	bounds := img.Bounds()
+	for name, f := range meta.Frames {
+		rect := image.Rect(f.X, f.Y, f.X+f.W, f.Y+f.H)
+		if !rect.In(bounds) || rect.Empty() {
+			return nil, fmt.Errorf("atlas frame %q %v is outside the image %v", name, rect, bounds)
+		}
+		atlas.frames[name] = img.SubImage(rect).(*ebiten.Image)
+	}
+
+	for name, a := range meta.Animations {
+		if len(a.Frames) == 0 {
+			return nil, fmt.Errorf("atlas animation %q has no frames", name)
+		}
+		anim := &Animation{
+			frames:        make([]*ebiten.Image, len(a.Frames)),
+			ticksPerFrame: ticksPerFrameForFPS(a.FPS),
+			loop:          a.Loop,
+		}
+		for i, frameName := range a.Frames {
+			frame, ok := atlas.frames[frameName]
+			if !ok {
+				return nil, fmt.Errorf("atlas animation %q references unknown frame %q", name, frameName)
+			}
+			anim.frames[i] = frame
+		}
+		atlas.animations[name] = anim
+	}
+
+	return atlas, nil
+}
+
+// Frame returns the named frame, or nil if the atlas has no such frame
+func (a *SpriteAtlas) Frame(name string) *ebiten.Image {
+	return a.frames[name]
+}
+
+// Animation returns the named animation, or nil if the atlas has no such animation
+func (a *SpriteAtlas) Animation(name string) *Animation {
+	return a.animations[name]
+}
+
+// --- Animation ---
+
+// Animation is an ordered sequence of frames played back at a fixed rate
+type Animation struct {
+	frames        []*ebiten.Image
+	ticksPerFrame int  // Update ticks each frame stays on screen
+	loop          bool // Whether playback wraps around or holds the last frame
+}
+
+// ticksPerFrameForFPS converts an animation rate to simulation steps (SimTPS per second)
+func ticksPerFrameForFPS(fps float64) int {
+	if fps <= 0 {
+		return 1
+	}
+	ticks := int(math.Round(SimTPS / fps))
+	if ticks < 1 {
+		ticks = 1
+	}
+	return ticks
+}
+
+// frameAt returns the frame shown after the given number of ticks
+func (a *Animation) frameAt(tick int) *ebiten.Image {
+	index := tick / a.ticksPerFrame
+	if a.loop {
+		index %= len(a.frames)
+	} else if index >= len(a.frames) {
+		index = len(a.frames) - 1
+	}
+	return a.frames[index]
+}
+
+// AnimationPlayer tracks playback of an animation for one entity
+type AnimationPlayer struct {
+	anim *Animation
+	tick int
+}
+
+// newAnimationPlayer starts playback at a random point so that entities sharing
+// an animation don't move in lockstep
+func newAnimationPlayer(anim *Animation) AnimationPlayer {
+	return AnimationPlayer{
+		anim: anim,
+		tick: rand.Intn(anim.ticksPerFrame * len(anim.frames)),
+	}
+}
+
+// Update advances playback by one tick
+func (p *AnimationPlayer) Update() {
+	p.tick++
+}
+
+// Frame returns the current frame of the animation
+func (p *AnimationPlayer) Frame() *ebiten.Image {
+	return p.anim.frameAt(p.tick)
+}
+
+// --- Generated Atlases ---
+
+// Frame counts for the generated animations
+const (
+	fishSwimFrames = 8
+	coinSpinFrames = 12
+	kelpWaveFrames = 8
+)
+
+// gridAtlasMeta lays out equally sized frames named "<prefix>_<i>" in a grid
+// and describes a looping animation over them
+func gridAtlasMeta(prefix string, count, columns, frameW, frameH int, fps float64) atlasMeta {
+	meta := atlasMeta{
+		Frames:     make(map[string]atlasFrame, count),
+		Animations: make(map[string]atlasAnimation, 1),
+	}
+	names := make([]string, count)
+	for i := 0; i < count; i++ {
+		names[i] = fmt.Sprintf("%s_%d", prefix, i)
+		meta.Frames[names[i]] = atlasFrame{
+			X: (i % columns) * frameW,
+			Y: (i / columns) * frameH,
+			W: frameW,
+			H: frameH,
+		}
+	}
+	meta.Animations[prefix] = atlasAnimation{Frames: names, FPS: fps, Loop: true}
+	return meta
+}
+
+// createFishSwimAtlas builds a swim cycle by bending the tail half of the
+// fish sprite (the fish faces right, so the tail is on the left)
+func createFishSwimAtlas(base image.Image) *SpriteAtlas {
+	bounds := base.Bounds()
+	frameW, frameH := bounds.Dx(), bounds.Dy()
+	columns := 4
+	rows := (fishSwimFrames + columns - 1) / columns
+	img := image.NewRGBA(image.Rect(0, 0, frameW*columns, frameH*rows))
+
+	tailLength := float64(frameW) * 0.45 // Portion of the body that bends
+	amplitude := float64(frameH) * 0.06  // Maximum tail displacement
+
+	for i := 0; i < fishSwimFrames; i++ {
+		phase := 2 * math.Pi * float64(i) / fishSwimFrames
+		originX := (i % columns) * frameW
+		originY := (i / columns) * frameH
+
+		for x := 0; x < frameW; x++ {
+			// Bend grows quadratically toward the tip of the tail
+			bend := math.Max(0, (tailLength-float64(x))/tailLength)
+			dy := int(math.Round(math.Sin(phase) * amplitude * bend * bend))
+			for y := 0; y < frameH; y++ {
+				srcY := y - dy
+				if srcY < 0 || srcY >= frameH {
+					continue
+				}
+				setPixel(img, originX+x, originY+y, base.At(bounds.Min.X+x, bounds.Min.Y+srcY))
+			}
+		}
+	}
+
+	atlas, err := newSpriteAtlas(ebiten.NewImageFromImage(img), gridAtlasMeta("swim", fishSwimFrames, columns, frameW, frameH, 10))
+	if err != nil {
+		panic("Failed to build fish atlas: " + err.Error())
+	}
+	atlas.source = img
+	return atlas
+}
+
+// createKelpAtlas builds a kelp strip animation whose wave pattern travels up the stem
+func createKelpAtlas(colors *PackColors) *SpriteAtlas {
+	frameW, frameH := 8, 32
+	img := image.NewRGBA(image.Rect(0, 0, frameW*kelpWaveFrames, frameH))
+	for i := 0; i < kelpWaveFrames; i++ {
+		phase := 2 * math.Pi * float64(i) / kelpWaveFrames
+		drawKelpStrip(img, i*frameW, frameW, frameH, phase, colors)
+	}
+
+	atlas, err := newSpriteAtlas(ebiten.NewImageFromImage(img), gridAtlasMeta("wave", kelpWaveFrames, kelpWaveFrames, frameW, frameH, 6))
+	if err != nil {
+		panic("Failed to build kelp atlas: " + err.Error())
+	}
+	return atlas
+}
+
+// coinSpinCache holds coin spin animations keyed by coin pixel size and palette
+var coinSpinCache = map[coinCacheKey]*Animation{}
+
+// coinSpinAnimation returns the spin animation for coins of the given size and
+// palette, rendering the frames the first time they are requested
+func coinSpinAnimation(size float64, colors *PackColors) *Animation {
+	cacheKey := coinCacheKey{size: int(math.Ceil(size)), colors: *colors}
+	if anim, ok := coinSpinCache[cacheKey]; ok {
+		return anim
+	}
+	key := cacheKey.size
+
+	face := coinSprite(size, colors)
+	img := ebiten.NewImage(key*coinSpinFrames, key)
+	for i := 0; i < coinSpinFrames; i++ {
+		// Squash the coin horizontally to fake a rotation about its vertical axis
+		angle := math.Pi * float64(i) / coinSpinFrames
+		scaleX := math.Max(0.1, math.Abs(math.Cos(angle)))
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-float64(key)/2, 0)
+		op.GeoM.Scale(scaleX, 1)
+		op.GeoM.Translate(float64(i*key)+float64(key)/2, 0)
+		// Darken the coin as it turns edge-on
+		shade := float32(0.7 + 0.3*scaleX)
+		op.ColorScale.Scale(shade, shade, shade, 1)
+		op.Filter = ebiten.FilterLinear
+		img.DrawImage(face, op)
+	}
+
+	atlas, err := newSpriteAtlas(img, gridAtlasMeta("spin", coinSpinFrames, coinSpinFrames, key, key, 15))
+	if err != nil {
+		panic("Failed to build coin atlas: " + err.Error())
+	}
+	anim := atlas.Animation("spin")
+	coinSpinCache[cacheKey] = anim
+	return anim
+}
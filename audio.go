@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+// --- Audio ---
+
+// audioSampleRate is the rate every sound is decoded (or resampled) to
+const audioSampleRate = 44100
+
+// Sound is a sound effect the game can play
+type Sound int
+
+const (
+	SoundCoin      Sound = iota // Coin picked up
+	SoundPass                   // Kelp pair passed (score++)
+	SoundCollision              // School hit a hazard
+	SoundMenu                   // Menu selection moved or confirmed
+	numSounds
+)
+
+// soundFiles are the asset names of the sound effects, without extension
+// (a .wav or .ogg file is accepted)
+var soundFiles = [numSounds]string{
+	SoundCoin:      "audio/coin",
+	SoundPass:      "audio/pass",
+	SoundCollision: "audio/collision",
+	SoundMenu:      "audio/menu",
+}
+
+// musicLayerFiles are the music loops, played in sync. The first layer always
+// plays; each further layer fades in as the run speeds up. Replacement loops
+// should all be the same length.
+var musicLayerFiles = []string{
+	"audio/music_base",
+	"audio/music_drums",
+	"audio/music_lead",
+}
+
+// AudioManager owns the audio context, the decoded sound effects and the music players
+type AudioManager struct {
+	context  *audio.Context
+	settings *Settings
+	sounds   [numSounds][]byte // 16-bit stereo PCM, nil if the sound is missing
+	music    []*audio.Player   // One looping player per music layer (missing layers are skipped)
+	layers   []int             // Layer index of each music player
+}
+
+// sharedAudio is created once: Ebitengine allows only one audio context per process
+var sharedAudio *AudioManager
+
+// loadAudio returns the audio manager, loading every sound on first use.
+// Sounds without a file are synthesized; unreadable files are logged and left silent.
+func loadAudio(settings *Settings) *AudioManager {
+	if sharedAudio != nil {
+		sharedAudio.settings = settings
+		return sharedAudio
+	}
+	a := &AudioManager{
+		context:  audio.NewContext(audioSampleRate),
+		settings: settings,
+	}
+	for s := Sound(0); s < numSounds; s++ {
+		pcm, err := loadAudioAsset(soundFiles[s])
+		if errors.Is(err, fs.ErrNotExist) {
+			pcm, err = synthesizedSounds[s](), nil
+		}
+		if err != nil {
+			log.Printf("Failed to load audio %s: %v", soundFiles[s], err)
+			continue
+		}
+		a.sounds[s] = pcm
+	}
+	for i, name := range musicLayerFiles {
+		pcm, err := loadAudioAsset(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			pcm, err = synthesizedMusic[i](), nil
+		}
+		if err != nil {
+			log.Printf("Failed to load audio %s: %v", name, err)
+			continue
+		}
+		loop := audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm)))
+		player, err := a.context.NewPlayer(loop)
+		if err != nil {
+			log.Printf("Failed to create music player for %s: %v", name, err)
+			continue
+		}
+		a.music = append(a.music, player)
+		a.layers = append(a.layers, i)
+	}
+	sharedAudio = a
+	return a
+}
+
+// loadAudioAsset decodes name.wav or name.ogg from the assets to 16-bit stereo PCM
+func loadAudioAsset(name string) ([]byte, error) {
+	decoders := []struct {
+		ext    string
+		decode func(io.Reader) (io.Reader, error)
+	}{
+		{".wav", func(r io.Reader) (io.Reader, error) { return wav.DecodeWithSampleRate(audioSampleRate, r) }},
+		{".ogg", func(r io.Reader) (io.Reader, error) { return vorbis.DecodeWithSampleRate(audioSampleRate, r) }},
+	}
+	for _, d := range decoders {
+		f, err := openAsset(name + d.ext)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		stream, err := d.decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s%s: %w", name, d.ext, err)
+		}
+		return io.ReadAll(stream)
+	}
+	return nil, fs.ErrNotExist
+}
+
+// Play starts a sound effect at the current SFX volume
+func (a *AudioManager) Play(s Sound) {
+	if a.sounds[s] == nil {
+		return
+	}
+	player := a.context.NewPlayerFromBytes(a.sounds[s])
+	player.SetVolume(a.settings.MasterVolume * a.settings.SFXVolume)
+	player.Play()
+}
+
+// UpdateMusic keeps the music playing and mixes its layers for the given
+// intensity (0 = calm start of a run, 1 = top speed)
+func (a *AudioManager) UpdateMusic(intensity float64) {
+	volume := a.settings.MasterVolume * a.settings.MusicVolume
+	for i, player := range a.music {
+		layer := a.layers[i]
+		mix := 1.0
+		if layer > 0 {
+			// Layers fade in one after another across the intensity range
+			steps := float64(len(musicLayerFiles) - 1)
+			mix = clamp01(intensity*steps - float64(layer-1))
+		}
+		player.SetVolume(volume * mix)
+		if !player.IsPlaying() {
+			player.Play()
+		}
+	}
+}
+
+// musicIntensity maps the run's speed multiplier to the music intensity
+func (g *Game) musicIntensity() float64 {
+	if !g.gameStarted || g.gameOver {
+		return 0
+	}
+	return clamp01((g.speedMultiplier - 2.0) / (MaxSpeedMultiplier - 2.0))
+}
+
+// clamp01 limits v to the range 0 to 1
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func init() {
+	registerConsoleCommand("volume", "master|music|sfx <0-1>", "Set a volume (saved in the settings)", func(g *Game, args []string) (string, error) {
+		if len(args) != 2 {
+			return "", errors.New("usage: volume master|music|sfx <0-1>")
+		}
+		var target *float64
+		switch args[0] {
+		case "master":
+			target = &g.settings.MasterVolume
+		case "music":
+			target = &g.settings.MusicVolume
+		case "sfx":
+			target = &g.settings.SFXVolume
+		default:
+			return "", fmt.Errorf("unknown volume %q", args[0])
+		}
+		v, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || v < 0 || v > 1 {
+			return "", fmt.Errorf("bad volume %q", args[1])
+		}
+		*target = v
+		g.saveSettings()
+		return fmt.Sprintf("%s volume %.2f", args[0], v), nil
+	})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"image"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Camera ---
+
+// The world (scenery, kelp, coins, jellyfish, the school and particles) is
+// drawn to its own image, then through the camera onto the canvas, so the
+// camera can follow the leader, zoom and shake it while the HUD and menus,
+// drawn afterwards, stay still. The camera is only a view: collisions and
+// steering work in world coordinates, which match the screen at rest.
+
+// Camera tuning (rates are per simulation step)
+const (
+	cameraFollowRate  = 0.05 // Fraction of the way to the leader's height moved each step
+	cameraZoomTop     = 0.9  // Zoom at top speed; it eases out from 1 as the speed climbs, showing more kelp ahead
+	cameraZoomRate    = 0.02 // Fraction of the way to the target zoom moved each step
+	cameraShakeCrash  = 14.0 // Shake strength in pixels when a hazard ends the run
+	cameraShakeGraze  = 3.0  // Shake strength while an invincible school overlaps a hazard
+	cameraShakeDecay  = 0.88 // Share of the shake strength kept each step
+	cameraShakeCutoff = 0.5  // Shakes weaker than this stop
+)
+
+// cameraPose is where the camera looks
+type cameraPose struct {
+	y              float64 // Height of the view center above or below the screen center, in world pixels
+	zoom           float64 // 1 shows the whole water column
+	shakeX, shakeY float64 // Shake offset, in screen pixels
+}
+
+// lerp blends from p to q as t goes from 0 to 1
+func (p cameraPose) lerp(q cameraPose, t float64) cameraPose {
+	mix := func(a, b float64) float64 { return a + (b-a)*t }
+	return cameraPose{mix(p.y, q.y), mix(p.zoom, q.zoom), mix(p.shakeX, q.shakeX), mix(p.shakeY, q.shakeY)}
+}
+
+// Camera maps the world onto the screen
+type Camera struct {
+	pose  cameraPose
+	prev  cameraPose // Pose before the latest simulation step
+	shake float64    // Current shake strength in pixels, decaying each step
+}
+
+// newCamera returns a camera at rest, showing the world exactly as drawn
+func newCamera() *Camera {
+	rest := cameraPose{zoom: 1}
+	return &Camera{pose: rest, prev: rest}
+}
+
+// Shake starts a shake of the given strength, unless a stronger one is under way
+func (c *Camera) Shake(strength float64) {
+	c.shake = math.Max(c.shake, strength)
+}
+
+// update moves the camera one step toward the leader's height and the zoom
+// for the current speed, and decays the shake. With reduced motion it
+// settles back to rest and doesn't shake.
+func (c *Camera) update(leaderY, speedMultiplier float64, reducedMotion bool) {
+	target := cameraPose{zoom: 1}
+	if !reducedMotion {
+		// Zoom out from 1 as the speed climbs from 2x to the top speed
+		t := clamp01((speedMultiplier - 2) / (MaxSpeedMultiplier - 2))
+		target.zoom = 1 + (cameraZoomTop-1)*t
+		target.y = leaderY - ScreenHeight/2
+	}
+	c.pose.zoom += (target.zoom - c.pose.zoom) * cameraZoomRate
+	c.pose.y += (target.y - c.pose.y) * cameraFollowRate
+
+	// Zoomed out, the whole water column fits with room to spare; follow the
+	// leader only as far as keeps its top and bottom on screen
+	limit := ScreenHeight / 2 * (1/c.pose.zoom - 1)
+	c.pose.y = math.Max(-limit, math.Min(limit, c.pose.y))
+
+	if reducedMotion || c.shake < cameraShakeCutoff {
+		c.shake = 0
+	}
+	c.pose.shakeX = (rand.Float64()*2 - 1) * c.shake
+	c.pose.shakeY = (rand.Float64()*2 - 1) * c.shake
+	c.shake *= cameraShakeDecay
+}
+
+// geoM maps world coordinates to screen coordinates for a pose. The zoom
+// is centered vertically but keeps the left edge in place, so zooming out
+// reveals more of what's ahead.
+func (p cameraPose) geoM() ebiten.GeoM {
+	var m ebiten.GeoM
+	m.Translate(0, -ScreenHeight/2-p.y)
+	m.Scale(p.zoom, p.zoom)
+	m.Translate(p.shakeX, ScreenHeight/2+p.shakeY)
+	return m
+}
+
+// draw draws the world image onto the screen through the camera, alpha of
+// the way from its previous to its current pose. Zoomed out, the screen
+// shows past the top and bottom of the world, so the world's first and last
+// rows are stretched over those bands: hanging kelp reaches up out of view
+// and the seabed continues down.
+func (c *Camera) draw(screen, world *ebiten.Image, alpha float64) {
+	m := c.prev.lerp(c.pose, alpha).geoM()
+	w, h := world.Bounds().Dx(), world.Bounds().Dy()
+	edges := []struct {
+		row image.Rectangle
+		y   float64 // Where the stretched row starts, in world pixels
+	}{
+		{image.Rect(0, 0, w, 1), -ScreenHeight},
+		{image.Rect(0, h-1, w, h), float64(h)},
+	}
+	for _, e := range edges {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1, ScreenHeight)
+		op.GeoM.Translate(0, e.y)
+		op.GeoM.Concat(m)
+		op.Filter = ebiten.FilterLinear
+		screen.DrawImage(world.SubImage(e.row).(*ebiten.Image), op)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM = m
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(world, op)
+}
+
+// toWorld converts a screen position to world coordinates
+func (c *Camera) toWorld(x, y float64) (float64, float64) {
+	m := c.pose.geoM()
+	m.Invert()
+	return m.Apply(x, y)
+}
@@ -1,6 +1,9 @@
 package main
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // --- Collision Logic ---
 
@@ -12,11 +15,26 @@ func checkAABBCollision(r1, r2 collisionRect) bool {
 		r1.y+r1.h > r2.y
 }
 
+// Circle radii used for school members in circle collision mode
+const (
+	leaderCollisionRadius   = PlayerSize * 0.35
+	followerCollisionRadius = FishSize * 0.4
+)
+
 // circleCollision represents a circle for collision detection
 type circleCollision struct {
 	x, y, radius float64
 }
 
+// collisionCircle is the jellyfish's stinging bell (the tentacles are harmless)
+func (j *Jellyfish) collisionCircle() circleCollision {
+	return circleCollision{
+		x:      j.x + j.size/2,
+		y:      j.y + j.size*0.3,
+		radius: j.size * 0.3,
+	}
+}
+
 // checkCircleCollision checks if two circles overlap
 func checkCircleCollision(c1, c2 circleCollision) bool {
 	dx := c1.x - c2.x
@@ -39,3 +57,141 @@ func checkCircleRectCollision(circle circleCollision, rect collisionRect) bool {
 	return distance < circle.radius
 }
 
+// --- Swept Collision ---
+
+// sweptCircleRectCollision checks if a circle moving by (dx, dy) relative to
+// the rectangle touches it at any point along the way, not just at the end.
+// The circle sweeps out a capsule, which overlaps the rectangle when the
+// movement segment comes within the radius of it.
+func sweptCircleRectCollision(circle circleCollision, dx, dy float64, rect collisionRect) bool {
+	x0, y0 := circle.x, circle.y
+	x1, y1 := circle.x+dx, circle.y+dy
+	return segmentRectDistance(x0, y0, x1, y1, rect) < circle.radius
+}
+
+// sweptRectRectCollision checks if a rectangle moving by (dx, dy) relative to
+// the target touches it at any point along the way. Growing the target by the
+// moving rectangle's size (their Minkowski sum) reduces this to a segment test
+// on the moving rectangle's corner.
+func sweptRectRectCollision(r collisionRect, dx, dy float64, target collisionRect) bool {
+	grown := collisionRect{
+		x: target.x - r.w,
+		y: target.y - r.h,
+		w: target.w + r.w,
+		h: target.h + r.h,
+	}
+	return segmentIntersectsRect(r.x, r.y, r.x+dx, r.y+dy, grown)
+}
+
+// segmentRectDistance returns the shortest distance between a line segment and
+// a rectangle (0 if they intersect). When they don't intersect, the closest
+// pair of points always includes a segment endpoint or a rectangle corner.
+func segmentRectDistance(x0, y0, x1, y1 float64, rect collisionRect) float64 {
+	if segmentIntersectsRect(x0, y0, x1, y1, rect) {
+		return 0
+	}
+	dist := math.Min(pointRectDistance(x0, y0, rect), pointRectDistance(x1, y1, rect))
+	corners := [4][2]float64{
+		{rect.x, rect.y},
+		{rect.x + rect.w, rect.y},
+		{rect.x, rect.y + rect.h},
+		{rect.x + rect.w, rect.y + rect.h},
+	}
+	for _, c := range corners {
+		dist = math.Min(dist, pointSegmentDistance(c[0], c[1], x0, y0, x1, y1))
+	}
+	return dist
+}
+
+// segmentIntersectsRect clips the segment against the rectangle's x and y
+// slabs and reports whether any part of it survives
+func segmentIntersectsRect(x0, y0, x1, y1 float64, rect collisionRect) bool {
+	tMin, tMax := 0.0, 1.0
+	clip := func(p, d, lo, hi float64) bool {
+		if d == 0 {
+			return p >= lo && p <= hi
+		}
+		t0, t1 := (lo-p)/d, (hi-p)/d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tMin = math.Max(tMin, t0)
+		tMax = math.Min(tMax, t1)
+		return tMin <= tMax
+	}
+	return clip(x0, x1-x0, rect.x, rect.x+rect.w) && clip(y0, y1-y0, rect.y, rect.y+rect.h)
+}
+
+// pointRectDistance returns the distance from a point to the nearest point of a rectangle
+func pointRectDistance(px, py float64, rect collisionRect) float64 {
+	closestX := math.Max(rect.x, math.Min(px, rect.x+rect.w))
+	closestY := math.Max(rect.y, math.Min(py, rect.y+rect.h))
+	return math.Hypot(px-closestX, py-closestY)
+}
+
+// pointSegmentDistance returns the distance from a point to the nearest point of a segment
+func pointSegmentDistance(px, py, x0, y0, x1, y1 float64) float64 {
+	dx, dy := x1-x0, y1-y0
+	lengthSq := dx*dx + dy*dy
+	t := 0.0
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, ((px-x0)*dx+(py-y0)*dy)/lengthSq))
+	}
+	return math.Hypot(px-(x0+t*dx), py-(y0+t*dy))
+}
+
+// --- Broad Phase ---
+
+// broadPhase is a sweep-and-prune index over the x extents of a set of bodies.
+// Everything in the game scrolls horizontally, so pruning on x alone leaves
+// only the handful of bodies near a query to test precisely.
+type broadPhase struct {
+	entries  []broadPhaseEntry // Sorted by minX after build
+	maxWidth float64           // Widest entry, bounds how far left of a query an overlapping entry can start
+}
+
+// broadPhaseEntry is one body's x extent and the caller's index for it
+type broadPhaseEntry struct {
+	minX, maxX float64
+	index      int
+}
+
+// reset empties the index, keeping its storage for the next build
+func (bp *broadPhase) reset() {
+	bp.entries = bp.entries[:0]
+	bp.maxWidth = 0
+}
+
+// insert adds a body covering minX to maxX under the caller's index
+func (bp *broadPhase) insert(index int, minX, maxX float64) {
+	bp.entries = append(bp.entries, broadPhaseEntry{minX: minX, maxX: maxX, index: index})
+	bp.maxWidth = math.Max(bp.maxWidth, maxX-minX)
+}
+
+// build sorts the entries; call it after inserting and before querying.
+// Bodies spawn at the right edge and scroll together, so the entries are
+// usually already in order and the insertion sort is close to linear.
+func (bp *broadPhase) build() {
+	for i := 1; i < len(bp.entries); i++ {
+		for j := i; j > 0 && bp.entries[j].minX < bp.entries[j-1].minX; j-- {
+			bp.entries[j], bp.entries[j-1] = bp.entries[j-1], bp.entries[j]
+		}
+	}
+}
+
+// query calls visit with the index of every body whose x extent overlaps minX
+// to maxX, in order of minX, until visit returns false
+func (bp *broadPhase) query(minX, maxX float64, visit func(index int) bool) {
+	// No entry starting before minX-maxWidth can reach minX
+	start := sort.Search(len(bp.entries), func(i int) bool {
+		return bp.entries[i].minX >= minX-bp.maxWidth
+	})
+	for _, e := range bp.entries[start:] {
+		if e.minX > maxX {
+			return
+		}
+		if e.maxX >= minX && !visit(e.index) {
+			return
+		}
+	}
+}
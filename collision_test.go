@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// --- Swept Collision ---
+
+// sweptSubsteps is how finely the ground truth samples the motion
+const sweptSubsteps = 2000
+
+// sampledCircleRectCollision is the ground truth for the swept test: the
+// static test at evenly spaced points along the motion, ends included
+func sampledCircleRectCollision(circle circleCollision, dx, dy float64, rect collisionRect, substeps int) bool {
+	for i := 0; i <= substeps; i++ {
+		t := float64(i) / float64(substeps)
+		c := circleCollision{x: circle.x + dx*t, y: circle.y + dy*t, radius: circle.radius}
+		if checkCircleRectCollision(c, rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSweptCircle compares the swept test with the sampled ground truth.
+// Sampling can miss a touch only between two substeps, where the circle is
+// at most half a substep from a sample, so the swept result must be at least
+// the sampled one at the real radius and at most the sampled one at a radius
+// half a substep larger. Away from grazing contacts the two agree exactly.
+func checkSweptCircle(t *testing.T, name string, circle circleCollision, dx, dy float64, rect collisionRect) {
+	t.Helper()
+	got := sweptCircleRectCollision(circle, dx, dy, rect)
+	lower := sampledCircleRectCollision(circle, dx, dy, rect, sweptSubsteps)
+	wider := circle
+	wider.radius += math.Hypot(dx, dy)/sweptSubsteps/2 + 1e-9
+	upper := sampledCircleRectCollision(wider, dx, dy, rect, sweptSubsteps)
+	if (lower && !got) || (got && !upper) {
+		t.Errorf("%s: swept = %v, sampled = %v (%v with tolerance); circle %+v moving (%g, %g), rect %+v",
+			name, got, lower, upper, circle, dx, dy, rect)
+	}
+}
+
+func TestSweptCircleRectMatchesSampled(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	for i := 0; i < 5000; i++ {
+		rect := collisionRect{
+			x: rng.Float64() * 400,
+			y: rng.Float64() * 400,
+			w: 1 + rng.Float64()*150,
+			h: 1 + rng.Float64()*150,
+		}
+		circle := circleCollision{
+			x:      -100 + rng.Float64()*700,
+			y:      -100 + rng.Float64()*700,
+			radius: 1 + rng.Float64()*40,
+		}
+		dx, dy := (rng.Float64()*2-1)*300, (rng.Float64()*2-1)*300
+		checkSweptCircle(t, fmt.Sprintf("random case %d", i), circle, dx, dy, rect)
+	}
+}
+
+func TestSweptCircleRectGrazingAndCorners(t *testing.T) {
+	rect := collisionRect{x: 100, y: 100, w: 80, h: 200}
+	const r = 10.0
+	// Offset of a 45 degree path past a corner, so it passes at distance d
+	diagonal := func(d float64) float64 { return d * math.Sqrt2 }
+
+	tests := []struct {
+		name   string
+		circle circleCollision
+		dx, dy float64
+		want   bool
+	}{
+		// Sliding along an edge: touching at exactly the radius isn't a hit
+		{"along top edge at radius", circleCollision{50, rect.y - r, r}, 200, 0, false},
+		{"along top edge inside radius", circleCollision{50, rect.y - r + 0.01, r}, 200, 0, true},
+		{"along left edge at radius", circleCollision{rect.x - r, 50, r}, 0, 300, false},
+		{"along left edge inside radius", circleCollision{rect.x - r + 0.01, 50, r}, 0, 300, true},
+
+		// Passing a corner diagonally, just outside and just inside the radius
+		{"past top-left corner outside", circleCollision{rect.x - 50 - diagonal(r+0.01), rect.y + 50, r}, 100, -100, false},
+		{"past top-left corner inside", circleCollision{rect.x - 50 - diagonal(r-0.01), rect.y + 50, r}, 100, -100, true},
+		{"past bottom-right corner outside", circleCollision{rect.x + rect.w + 50 + diagonal(r+0.01), rect.y + rect.h - 50, r}, -100, 100, false},
+		{"past bottom-right corner inside", circleCollision{rect.x + rect.w + 50 + diagonal(r-0.01), rect.y + rect.h - 50, r}, -100, 100, true},
+
+		// Ending just short of a corner, approaching along the diagonal
+		{"stops short of corner", circleCollision{rect.x - 40, rect.y - 40, r}, 40 - (r+0.01)/math.Sqrt2, 40 - (r+0.01)/math.Sqrt2, false},
+		{"reaches corner", circleCollision{rect.x - 40, rect.y - 40, r}, 40 - (r-0.01)/math.Sqrt2, 40 - (r-0.01)/math.Sqrt2, true},
+
+		// Tunneling: both ends are clear of the kelp but the path crosses it
+		{"tunnels through", circleCollision{rect.x - 50, 200, r}, rect.w + 100, 0, true},
+		{"tunnels through diagonally", circleCollision{rect.x - 50, rect.y - 50, r}, rect.w + 100, rect.h + 100, true},
+
+		// Not moving at all is the static test
+		{"resting inside", circleCollision{140, 200, r}, 0, 0, true},
+		{"resting clear", circleCollision{40, 200, r}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		if got := sweptCircleRectCollision(tt.circle, tt.dx, tt.dy, rect); got != tt.want {
+			t.Errorf("%s: swept = %v, want %v", tt.name, got, tt.want)
+		}
+		checkSweptCircle(t, tt.name, tt.circle, tt.dx, tt.dy, rect)
+	}
+}
+
+// rectsTouch is the static test behind sweptRectRectCollision: rectangles
+// touch when they overlap or share an edge
+func rectsTouch(a, b collisionRect) bool {
+	return a.x <= b.x+b.w && a.x+a.w >= b.x && a.y <= b.y+b.h && a.y+a.h >= b.y
+}
+
+// sampledRectRectCollision is the ground truth for the swept rectangle test
+func sampledRectRectCollision(r collisionRect, dx, dy float64, target collisionRect, substeps int) bool {
+	for i := 0; i <= substeps; i++ {
+		t := float64(i) / float64(substeps)
+		moved := collisionRect{x: r.x + dx*t, y: r.y + dy*t, w: r.w, h: r.h}
+		if rectsTouch(moved, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSweptRect compares the swept rectangle test with the sampled ground
+// truth, with the same tolerance as checkSweptCircle: the moving rectangle
+// is grown by half a substep on every side for the upper bound
+func checkSweptRect(t *testing.T, name string, r collisionRect, dx, dy float64, target collisionRect) {
+	t.Helper()
+	got := sweptRectRectCollision(r, dx, dy, target)
+	lower := sampledRectRectCollision(r, dx, dy, target, sweptSubsteps)
+	gx, gy := math.Abs(dx)/sweptSubsteps/2+1e-9, math.Abs(dy)/sweptSubsteps/2+1e-9
+	wider := collisionRect{x: r.x - gx, y: r.y - gy, w: r.w + 2*gx, h: r.h + 2*gy}
+	upper := sampledRectRectCollision(wider, dx, dy, target, sweptSubsteps)
+	if (lower && !got) || (got && !upper) {
+		t.Errorf("%s: swept = %v, sampled = %v (%v with tolerance); rect %+v moving (%g, %g), target %+v",
+			name, got, lower, upper, r, dx, dy, target)
+	}
+}
+
+func TestSweptRectRectMatchesSampled(t *testing.T) {
+	rng := rand.New(rand.NewSource(37))
+	for i := 0; i < 5000; i++ {
+		target := collisionRect{
+			x: rng.Float64() * 400,
+			y: rng.Float64() * 400,
+			w: 1 + rng.Float64()*150,
+			h: 1 + rng.Float64()*150,
+		}
+		// Mask bands are short and wide, so cover thin rectangles too
+		r := collisionRect{
+			x: -100 + rng.Float64()*700,
+			y: -100 + rng.Float64()*700,
+			w: 0.5 + rng.Float64()*60,
+			h: 0.5 + rng.Float64()*20,
+		}
+		dx, dy := (rng.Float64()*2-1)*300, (rng.Float64()*2-1)*300
+		checkSweptRect(t, fmt.Sprintf("random case %d", i), r, dx, dy, target)
+	}
+}
+
+func TestSweptRectRectEdgesAndCorners(t *testing.T) {
+	target := collisionRect{x: 100, y: 100, w: 80, h: 200}
+	r := collisionRect{w: 20, h: 10}
+	at := func(x, y float64) collisionRect { return collisionRect{x, y, r.w, r.h} }
+
+	tests := []struct {
+		name   string
+		r      collisionRect
+		dx, dy float64
+		want   bool
+	}{
+		// Sliding along an edge: sharing the edge counts, a hair away doesn't
+		{"along top edge touching", at(0, target.y-r.h), 300, 0, true},
+		{"along top edge clear", at(0, target.y-r.h-0.01), 300, 0, false},
+		{"along right edge touching", at(target.x+target.w, 0), 0, 400, true},
+		{"along right edge clear", at(target.x+target.w+0.01, 0), 0, 400, false},
+
+		// Passing a corner diagonally: the moving rectangle's bottom-right
+		// corner travels along a line just missing or just clipping the target's top-left corner
+		{"past top-left corner clear", at(target.x-r.w-50.01, target.y-r.h+50), 100, -100, false},
+		{"past top-left corner clipping", at(target.x-r.w-49.99, target.y-r.h+50), 100, -100, true},
+
+		// Ending just short of the target, and reaching it exactly
+		{"stops short", at(0, 150), target.x - r.w - 0.01, 0, false},
+		{"reaches edge", at(0, 150), target.x - r.w, 0, true},
+
+		// Tunneling: both ends are clear but the path crosses the target
+		{"tunnels through", at(0, 150), 300, 0, true},
+		{"tunnels through diagonally", at(0, 0), 400, 500, true},
+
+		// Not moving at all is the static test
+		{"resting inside", at(120, 150), 0, 0, true},
+		{"resting clear", at(0, 150), 0, 0, false},
+	}
+	for _, tt := range tests {
+		if got := sweptRectRectCollision(tt.r, tt.dx, tt.dy, target); got != tt.want {
+			t.Errorf("%s: swept = %v, want %v", tt.name, got, tt.want)
+		}
+		checkSweptRect(t, tt.name, tt.r, tt.dx, tt.dy, target)
+	}
+}
+
+// --- Broad Phase ---
+
+// randomSpans returns n x extents scattered over the view, of widths up to maxWidth
+func randomSpans(rng *rand.Rand, n int, maxWidth float64) [][2]float64 {
+	spans := make([][2]float64, n)
+	for i := range spans {
+		minX := -100 + rng.Float64()*(MaxViewWidth+200)
+		spans[i] = [2]float64{minX, minX + rng.Float64()*maxWidth}
+	}
+	return spans
+}
+
+func TestBroadPhaseQueryMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(38))
+	var bp broadPhase
+	for round := 0; round < 200; round++ {
+		// Mix narrow and wide bodies, so the maxWidth bound matters
+		spans := randomSpans(rng, rng.Intn(60), 20+rng.Float64()*400)
+		bp.reset()
+		for i, s := range spans {
+			bp.insert(i, s[0], s[1])
+		}
+		bp.build()
+
+		for q := 0; q < 50; q++ {
+			minX := -150 + rng.Float64()*(MaxViewWidth+300)
+			maxX := minX + rng.Float64()*150
+
+			want := map[int]bool{}
+			for i, s := range spans {
+				if s[1] >= minX && s[0] <= maxX {
+					want[i] = true
+				}
+			}
+			got := map[int]bool{}
+			bp.query(minX, maxX, func(i int) bool {
+				if got[i] {
+					t.Errorf("round %d: query(%g, %g) visited %d twice", round, minX, maxX, i)
+				}
+				got[i] = true
+				return true
+			})
+			if len(got) != len(want) {
+				t.Errorf("round %d: query(%g, %g) found %d bodies, brute force %d", round, minX, maxX, len(got), len(want))
+				continue
+			}
+			for i := range want {
+				if !got[i] {
+					t.Errorf("round %d: query(%g, %g) missed body %d at %v", round, minX, maxX, i, spans[i])
+				}
+			}
+		}
+	}
+}
+
+// collisionBench is a scene of fish among kelp
+type collisionBench struct {
+	fish []circleCollision
+	kelp []collisionRect
+}
+
+// Scene sizes the collision benchmarks run at, from a small school among a
+// few kelp to a crowded screen, so the growth of each approach shows
+var (
+	benchFishCounts = []int{15, 100, 300}
+	benchKelpCounts = []int{8, 24, 64}
+)
+
+func newCollisionBench(numFish, numKelp int) *collisionBench {
+	rng := rand.New(rand.NewSource(38))
+	s := &collisionBench{}
+	for i := 0; i < numFish; i++ {
+		s.fish = append(s.fish, circleCollision{
+			x:      PlayerX + rng.Float64()*300,
+			y:      rng.Float64() * ScreenHeight,
+			radius: followerCollisionRadius,
+		})
+	}
+	for i := 0; i < numKelp; i++ {
+		s.kelp = append(s.kelp, collisionRect{
+			x: rng.Float64() * MaxViewWidth,
+			y: rng.Float64() * ScreenHeight / 2,
+			w: 80,
+			h: 100 + rng.Float64()*300,
+		})
+	}
+	return s
+}
+
+// benchmarkCollisions runs step once per iteration for every scene size
+func benchmarkCollisions(b *testing.B, step func(s *collisionBench) int) {
+	for _, numFish := range benchFishCounts {
+		for _, numKelp := range benchKelpCounts {
+			b.Run(fmt.Sprintf("fish=%d/kelp=%d", numFish, numKelp), func(b *testing.B) {
+				s := newCollisionBench(numFish, numKelp)
+				hits := 0
+				b.ResetTimer()
+				for n := 0; n < b.N; n++ {
+					hits += step(s)
+				}
+				_ = hits
+			})
+		}
+	}
+}
+
+// BenchmarkCollisionsBruteForce tests every fish against every kelp, one step per iteration
+func BenchmarkCollisionsBruteForce(b *testing.B) {
+	benchmarkCollisions(b, func(s *collisionBench) int {
+		hits := 0
+		for _, f := range s.fish {
+			for _, k := range s.kelp {
+				if sweptCircleRectCollision(f, 0, PlayerSpeed, k) {
+					hits++
+				}
+			}
+		}
+		return hits
+	})
+}
+
+// BenchmarkCollisionsBroadPhase rebuilds the index and queries it for every fish, one step per iteration
+func BenchmarkCollisionsBroadPhase(b *testing.B) {
+	var bp broadPhase
+	benchmarkCollisions(b, func(s *collisionBench) int {
+		bp.reset()
+		for i, k := range s.kelp {
+			bp.insert(i, k.x, k.x+k.w)
+		}
+		bp.build()
+		hits := 0
+		for _, f := range s.fish {
+			bp.query(f.x-f.radius, f.x+f.radius, func(i int) bool {
+				if sweptCircleRectCollision(f, 0, PlayerSpeed, s.kelp[i]) {
+					hits++
+				}
+				return true
+			})
+		}
+		return hits
+	})
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// --- Developer Console ---
+
+// Console layout (drops down from the top of the screen)
+const (
+	consoleHeight      = 280
+	consoleLineSpacing = 20
+	consoleMaxLines    = 12 // Output lines kept on screen
+	consoleTextScale   = 1.5
+)
+
+// consoleCommand is a command the console can run. run returns the text to
+// print, or an error to print instead.
+type consoleCommand struct {
+	usage string // Argument summary shown by help, e.g. "<multiplier>"
+	help  string // One-line description shown by help
+	run   func(g *Game, args []string) (string, error)
+}
+
+// consoleCommands holds every registered command by name
+var consoleCommands = map[string]consoleCommand{}
+
+// registerConsoleCommand adds a command to the console. Register new commands
+// from an init function in the file that implements them.
+func registerConsoleCommand(name, usage, help string, run func(g *Game, args []string) (string, error)) {
+	consoleCommands[name] = consoleCommand{usage: usage, help: help, run: run}
+}
+
+// console is the drop-down command line toggled with the backtick key. It
+// survives restarts so its output and history stay visible.
+type console struct {
+	open     bool
+	input    string   // Text typed at the prompt
+	lines    []string // Output, oldest first
+	history  []string // Commands run, oldest first
+	recalled int      // Position in history while browsing with Up/Down (len(history) = not browsing)
+}
+
+// print adds a line of output, dropping the oldest lines past consoleMaxLines
+func (c *console) print(line string) {
+	c.lines = append(c.lines, line)
+	if len(c.lines) > consoleMaxLines {
+		c.lines = c.lines[len(c.lines)-consoleMaxLines:]
+	}
+}
+
+// updateConsole handles the console's keys. It returns true while the console
+// is open, in which case the rest of the game doesn't see the input (and the
+// simulation is paused).
+func (g *Game) updateConsole() bool {
+	c := g.console
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		c.open = !c.open
+		return true
+	}
+	if !c.open {
+		return false
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		c.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		line := strings.TrimSpace(c.input)
+		c.input = ""
+		if line != "" {
+			c.print("> " + line)
+			c.history = append(c.history, line)
+			g.runConsoleCommand(line)
+		}
+		c.recalled = len(c.history)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		if len(c.input) > 0 {
+			c.input = c.input[:len(c.input)-1]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		if c.recalled > 0 {
+			c.recalled--
+			c.input = c.history[c.recalled]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		if c.recalled < len(c.history) {
+			c.recalled++
+			c.input = ""
+			if c.recalled < len(c.history) {
+				c.input = c.history[c.recalled]
+			}
+		}
+	default:
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if r != '`' && r < 128 {
+				c.input += string(r)
+			}
+		}
+	}
+	return true
+}
+
+// runConsoleCommand parses a line into a command name and arguments and runs it
+func (g *Game) runConsoleCommand(line string) {
+	c := g.console // Restarting commands replace the rest of the game state
+	fields := strings.Fields(line)
+	cmd, ok := consoleCommands[strings.ToLower(fields[0])]
+	if !ok {
+		c.print(fmt.Sprintf("Unknown command %q (try help)", fields[0]))
+		return
+	}
+	out, err := cmd.run(g, fields[1:])
+	if err != nil {
+		c.print("Error: " + err.Error())
+		return
+	}
+	if out != "" {
+		c.print(out)
+	}
+}
+
+// drawConsole draws the console panel over everything else
+func (g *Game) drawConsole(screen *ebiten.Image) {
+	c := g.console
+	ebitenutil.DrawRect(screen, 0, 0, display.width, consoleHeight, color.RGBA{10, 20, 30, 220})
+	ebitenutil.DrawRect(screen, 0, consoleHeight, display.width, 2, color.RGBA{120, 200, 255, 255})
+
+	drawLine := func(s string, y float64, clr color.Color) {
+		op := &text.DrawOptions{}
+		op.GeoM.Scale(consoleTextScale, consoleTextScale)
+		op.GeoM.Translate(10, y)
+		op.ColorScale.ScaleWithColor(clr)
+		text.Draw(screen, s, uiFace, op)
+	}
+	for i, line := range c.lines {
+		drawLine(line, 10+float64(i*consoleLineSpacing), color.RGBA{200, 200, 200, 255})
+	}
+	drawLine("> "+c.input+"_", consoleHeight-30, color.White)
+}
+
+// saveScreenshot writes the screen to a timestamped PNG in the working directory
+func saveScreenshot(screen *ebiten.Image) (string, error) {
+	bounds := screen.Bounds()
+	img := image.NewRGBA(bounds)
+	screen.ReadPixels(img.Pix)
+
+	name := fmt.Sprintf("screenshot-%s.png", time.Now().Format("20060102-150405"))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// --- Built-in Commands ---
+
+// errNotStarted is returned by commands that need a run in progress
+var errNotStarted = errors.New("pick a difficulty first")
+
+func init() {
+	registerConsoleCommand("help", "", "List commands", func(g *Game, args []string) (string, error) {
+		names := make([]string, 0, len(consoleCommands))
+		for name := range consoleCommands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			cmd := consoleCommands[name]
+			g.console.print(strings.TrimSpace(name+" "+cmd.usage) + " - " + cmd.help)
+		}
+		return "", nil
+	})
+
+	registerConsoleCommand("speed", "<2-5>", "Jump to the point in the run where the speed multiplier reaches this value", func(g *Game, args []string) (string, error) {
+		v, err := floatArg(args, 2, MaxSpeedMultiplier)
+		if err != nil {
+			return "", err
+		}
+		if !g.gameStarted {
+			return "", errNotStarted
+		}
+		// The multiplier is derived from gameTime, so move gameTime rather than
+		// overriding the multiplier (which the next step would undo)
+		g.gameTime = int((v - 2) * g.accelerationRate())
+		g.cheated = true
+		return fmt.Sprintf("Speed %.2fx (step %d)", v, g.gameTime), nil
+	})
+
+	registerConsoleCommand("spawn", "kelp|coin|jellyfish", "Spawn a kelp pair with coins, a coin or a jellyfish at the right edge", func(g *Game, args []string) (string, error) {
+		if len(args) != 1 {
+			return "", errors.New("usage: spawn kelp|coin|jellyfish")
+		}
+		if !g.gameStarted {
+			return "", errNotStarted
+		}
+		switch args[0] {
+		case "kelp":
+			g.spawnObstaclePair()
+		case "coin":
+			g.spawnCoin(SpawnX, ScreenHeight/2-8)
+		case "jellyfish":
+			g.spawnJellyfish()
+		default:
+			return "", fmt.Errorf("can't spawn %q", args[0])
+		}
+		g.cheated = true
+		return "Spawned " + args[0], nil
+	})
+
+	registerConsoleCommand("god", "on|off", "Ignore collisions with hazards", func(g *Game, args []string) (string, error) {
+		if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+			return "", errors.New("usage: god on|off")
+		}
+		g.godMode = args[0] == "on"
+		g.cheated = g.cheated || g.godMode
+		return "God mode " + args[0], nil
+	})
+
+	registerConsoleCommand("seed", "[n]", "Show the run's seed, or restart the run with seed n", func(g *Game, args []string) (string, error) {
+		if len(args) == 0 {
+			return fmt.Sprintf("Seed %d", g.seed), nil
+		}
+		seed, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("bad seed %q", args[0])
+		}
+		difficulty, started, c := g.difficulty, g.gameStarted, g.console
+		*g = *NewSeededGame(g.settings, seed)
+		g.difficulty, g.gameStarted, g.console = difficulty, started, c
+		return fmt.Sprintf("Restarted with seed %d", seed), nil
+	})
+
+	registerConsoleCommand("school", "add|remove <n>", "Add or remove followers", func(g *Game, args []string) (string, error) {
+		if len(args) != 2 || (args[0] != "add" && args[0] != "remove") {
+			return "", errors.New("usage: school add|remove <n>")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("bad count %q", args[1])
+		}
+		if args[0] == "add" {
+			for i := 0; i < n; i++ {
+				g.addFollower()
+			}
+		} else {
+			g.removeFollowers(n)
+		}
+		g.cheated = true
+		return fmt.Sprintf("School has %d followers", len(g.fish)), nil
+	})
+
+	registerConsoleCommand("timescale", "<0.05-4>", "Run the simulation slower or faster than real time", func(g *Game, args []string) (string, error) {
+		v, err := floatArg(args, 0.05, 4)
+		if err != nil {
+			return "", err
+		}
+		g.timeScale = v
+		g.cheated = g.cheated || v != 1
+		return fmt.Sprintf("Time scale %.2f", v), nil
+	})
+
+	registerConsoleCommand("screenshot", "", "Save the screen (without the console) as a PNG", func(g *Game, args []string) (string, error) {
+		g.screenshotRequested = true
+		return "Screenshot will be saved on the next frame", nil
+	})
+}
+
+// floatArg parses the single argument of a command as a number in [min, max]
+func floatArg(args []string, min, max float64) (float64, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected one number")
+	}
+	v, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", args[0])
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("%g is outside %g-%g", v, min, max)
+	}
+	return v, nil
+}
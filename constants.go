@@ -16,9 +16,14 @@ const (
 	CircleOffsetX    = -100.0 // X offset of the circle center behind the leader
 	PlayerX          = 200.0  // Fixed X position of the leader
 	FishWanderRadius = 40.0   // Radius within which fish can wander from their base position
-	FishWanderIntervalMin = 60   // Minimum frames between wander target changes (1 second at 60 FPS)
-	FishWanderIntervalMax = 180  // Maximum frames between wander target changes (3 seconds at 60 FPS)
+	FishWanderIntervalMin = 60   // Minimum simulation steps between wander target changes (1 second at SimTPS)
+	FishWanderIntervalMax = 180  // Maximum simulation steps between wander target changes (3 seconds at SimTPS)
 	NumBackgroundFish = 8     // Number of background ambient fish
-	NumBubbles        = 15    // Number of floating bubbles
+	NumBubbles        = 300   // Number of floating bubbles
+	MaxSpeedMultiplier = 5.0  // Cap on the scroll speed multiplier
+	JellyfishSize      = 56   // Size of a jellyfish
+	JellyfishDriftFactor = 0.8 // Fraction of the scroll speed jellyfish drift at (they swim against the current a little)
+	JellyfishBobSpeed  = 0.03 // Bobbing phase advance per step (radians)
+	JellyfishBobHeight = 40.0 // Vertical distance jellyfish bob either side of their base height
 )
 
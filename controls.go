@@ -0,0 +1,144 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Controls (Rebinding) Screen ---
+
+// controlsScreen is the state of the key rebinding screen
+type controlsScreen struct {
+	selected  Action // Action being rebound
+	capturing bool   // Waiting for the next key press to add to the selected action
+	notice    string // Why the last key pressed couldn't be bound ("" shows the help)
+	ui        *UI
+}
+
+// newControlsScreen opens the rebinding screen
+func newControlsScreen() *controlsScreen {
+	rows := VStack(4)
+	rows.Align = AlignStretch
+	for a := Action(0); a < numActions; a++ {
+		rows.Children = append(rows.Children, &Button{
+			Content: &Label{Text: "%s", Value: func(g *Game) interface{} { return g.controls.rowText(g, a) }, Scale: 2.0},
+			Padding: 8,
+			OnPress: func(g *Game) {
+				g.controls.selected = a
+				g.controls.capturing = true
+				g.controls.notice = ""
+			},
+			Active: func(g *Game) bool { return g.controls.capturing && g.controls.selected == a },
+		})
+	}
+
+	reset := textButton("controls.reset", 1.5, func(g *Game) {
+		g.input.ResetBindings(g.settings)
+		g.saveSettings()
+	}, ebiten.KeyR)
+	reset.Content.(*Label).Subtle = true
+
+	ui := NewUI(&Panel{
+		widget:  widget{MinW: 700},
+		Padding: 40,
+		Child: VStack(0,
+			&Label{Text: "controls.title", Scale: 3.0},
+			vgap(30),
+			rows,
+			vgap(30),
+			reset,
+			vgap(10),
+			&Label{Text: "%s", Value: func(g *Game) interface{} { return g.controls.helpText() }, Scale: 1.5, Subtle: true},
+		),
+	})
+	ui.OnBack = func(g *Game) { g.controls = nil }
+	return &controlsScreen{ui: ui}
+}
+
+// rowText describes the keys bound to an action
+func (c *controlsScreen) rowText(g *Game, a Action) string {
+	if c.capturing && c.selected == a {
+		return tr("controls.capturing", tr(actionLabels[a]))
+	}
+	return tr("controls.row", tr(actionLabels[a]), keyNames(g.input.Keys(a)))
+}
+
+// helpText is the line under the rows: why a key was refused, or what the keys do
+func (c *controlsScreen) helpText() string {
+	switch {
+	case c.notice != "":
+		return c.notice
+	case c.capturing:
+		return tr("controls.captureHelp")
+	}
+	return tr("controls.help")
+}
+
+// keyNames lists key names for display, e.g. "ArrowUp, W"
+func keyNames(keys []ebiten.Key) string {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// updateControlsScreen handles key capture, and otherwise navigation. The
+// arrow keys, Enter and Escape always work here in addition to the bound
+// keys, so a bad layout can't lock the player out of this screen.
+func (g *Game) updateControlsScreen() {
+	c := g.controls
+
+	if c.capturing {
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+			c.capturing = false
+			c.notice = ""
+		case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+			// Clear the action's keys and keep capturing, so new ones can be added
+			g.input.ClearKeys(c.selected, g.settings)
+			g.saveSettings()
+			c.notice = ""
+		default:
+			keys := inpututil.AppendJustPressedKeys(nil)
+			if len(keys) > 0 {
+				c.capture(g, keys[0])
+			}
+		}
+		return
+	}
+
+	c.ui.Update(g)
+}
+
+// capture adds a pressed key to the selected action. Keys the game reserves
+// and keys bound to another action are refused, so no key does two things.
+func (c *controlsScreen) capture(g *Game, key ebiten.Key) {
+	if !keyAllowed(key, c.selected) {
+		c.notice = tr("controls.reserved", key.String())
+		return
+	}
+	if other := g.input.BoundTo(key); other != noAction && other != c.selected {
+		c.notice = tr("controls.taken", key.String(), tr(actionLabels[other]))
+		return
+	}
+	g.input.AddKey(c.selected, key, g.settings)
+	g.saveSettings()
+	c.capturing = false
+	c.notice = ""
+}
+
+// drawControlsScreen draws the rebinding screen over the menu background
+func (g *Game) drawControlsScreen(screen *ebiten.Image) {
+	g.controls.ui.Draw(screen, g)
+}
+
+// saveSettings persists the settings, logging (not failing) on error
+func (g *Game) saveSettings() {
+	if err := g.settings.Save(); err != nil {
+		log.Printf("Failed to save settings: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// --- Debug Overlay ---
+
+// Colors of the debug overlay
+var (
+	debugHitboxColor = color.RGBA{255, 60, 60, 255} // School collision shapes (whichever mode is active)
+	debugObsColor    = color.RGBA{255, 255, 0, 255} // Kelp rectangles and jellyfish circles
+	debugCoinColor   = color.RGBA{255, 170, 0, 255} // Coin collection circles
+	debugBaseColor   = color.RGBA{0, 255, 255, 160} // Follower base offsets and wander radius
+	debugTargetColor = color.RGBA{255, 0, 255, 255} // Follower wander targets
+	debugPanelColor  = color.RGBA{0, 0, 0, 160}     // Backing behind the stats
+)
+
+// Debug stats panel layout (top-right corner)
+const (
+	debugPanelWidth  = 300
+	debugPanelX      = ScreenWidth - debugPanelWidth - 10
+	debugPanelY      = 10
+	debugLineSpacing = 20
+)
+
+// drawDebugOverlay draws the hitboxes and wander targets of the debug overlay (F3), in the world
+func (g *Game) drawDebugOverlay(screen *ebiten.Image) {
+	strokeCircle := func(cx, cy, r float64, clr color.Color) {
+		vector.StrokeCircle(screen, float32(cx), float32(cy), float32(r), 1, clr, true)
+	}
+	strokeRect := func(r collisionRect, clr color.Color) {
+		vector.StrokeRect(screen, float32(r.x), float32(r.y), float32(r.w), float32(r.h), 1, clr, false)
+	}
+
+	// Kelp and coins
+	for _, obs := range g.obstacles {
+		strokeRect(collisionRect{x: g.interpolate(obs.prevX, obs.x), y: obs.y, w: obs.width, h: obs.height}, debugObsColor)
+	}
+	for _, coin := range g.coins {
+		if !coin.collected {
+			x := g.interpolate(coin.prevX, coin.x)
+			strokeCircle(x+coin.size/2, coin.y+coin.size/2, coin.size*0.4, debugCoinColor)
+		}
+	}
+	for _, jelly := range g.jellyfish {
+		c := jelly.collisionCircle()
+		dx, dy := g.interpolate(jelly.prevX, jelly.x)-jelly.x, g.interpolate(jelly.prevY, jelly.y)-jelly.y
+		strokeCircle(c.x+dx, c.y+dy, c.radius, debugObsColor)
+	}
+
+	// School hitboxes in the active collision mode
+	leaderY := g.interpolate(g.prevPlayerY, g.playerY)
+	g.strokeHitbox(screen, PlayerX, leaderY, PlayerSize, leaderCollisionRadius, debugHitboxColor)
+
+	// Followers: base offset with its wander radius, current wander target, and a
+	// line from the fish to where it's heading
+	for _, fish := range g.fish {
+		x, y := g.interpolate(fish.prevX, fish.x), g.interpolate(fish.prevY, fish.y)
+		g.strokeHitbox(screen, x, y, FishSize, followerCollisionRadius, debugHitboxColor)
+
+		baseX := PlayerX + fish.offsetX + FishSize/2
+		baseY := leaderY + fish.offsetY + FishSize/2
+		strokeCircle(baseX, baseY, FishWanderRadius, debugBaseColor)
+		vector.FillCircle(screen, float32(baseX), float32(baseY), 2, debugBaseColor, true)
+
+		targetX := PlayerX + fish.targetOffsetX + FishSize/2
+		targetY := leaderY + fish.targetOffsetY + FishSize/2
+		vector.FillCircle(screen, float32(targetX), float32(targetY), 3, debugTargetColor, true)
+		vector.StrokeLine(screen, float32(x+FishSize/2), float32(y+FishSize/2), float32(targetX), float32(targetY), 1, debugTargetColor, true)
+	}
+
+}
+
+// drawDebugStats draws the debug overlay's stats panel, in screen space
+func (g *Game) drawDebugStats(screen *ebiten.Image) {
+	panelX := debugPanelX + display.anchorOffset(AnchorRight)
+	vector.FillRect(screen, float32(panelX), debugPanelY, debugPanelWidth, float32(len(g.debugHUD.elements)*debugLineSpacing+10), debugPanelColor, false)
+	g.debugHUD.Draw(screen, g)
+}
+
+// strokeHitbox outlines the shape a school member drawn size pixels wide at
+// (x, y) collides as: its sprite mask or its circle, depending on the collision mode
+func (g *Game) strokeHitbox(screen *ebiten.Image, x, y, size, radius float64, clr color.Color) {
+	if g.settings.CollisionMode == CollisionMask && g.pack.fishMask != nil {
+		for _, r := range g.pack.fishMask.rects(x, y, size) {
+			vector.StrokeRect(screen, float32(r.x), float32(r.y), float32(r.w), float32(r.h), 1, clr, false)
+		}
+		return
+	}
+	vector.StrokeCircle(screen, float32(x+size/2), float32(y+size/2), float32(radius), 1, clr, true)
+}
+
+// newDebugHUD describes the stats panel of the debug overlay
+func newDebugHUD() *HUD {
+	lines := []HUDElement{
+		{Format: "FPS: %.1f", Value: func(g *Game) interface{} { return ebiten.ActualFPS() }},
+		{Format: "TPS: %.1f", Value: func(g *Game) interface{} { return ebiten.ActualTPS() }},
+		{Format: "Step: %d", Value: func(g *Game) interface{} { return g.gameTime }},
+		{Format: "Spawn timer: %d", Value: func(g *Game) interface{} { return g.spawnTimer }},
+		{Format: "Speed: %.3fx", Value: func(g *Game) interface{} { return g.speedMultiplier }},
+		{Format: "Fish: %d", Value: func(g *Game) interface{} { return len(g.fish) + 1 }}, // Followers plus the leader
+		{Format: "Kelp: %d", Value: func(g *Game) interface{} { return len(g.obstacles) }},
+		{Format: "Coins: %d", Value: func(g *Game) interface{} { return len(g.coins) }},
+		{Format: "Jellyfish: %d", Value: func(g *Game) interface{} { return len(g.jellyfish) }},
+		{Format: "Background fish: %d", Value: func(g *Game) interface{} { return len(g.backgroundFish) }},
+		{Format: "Particles: %d", Value: func(g *Game) interface{} { return g.effects.Len() }},
+		{Format: "Collision: %s", Value: func(g *Game) interface{} { return g.settings.CollisionMode }},
+		{Format: "Seed: %d", Value: func(g *Game) interface{} { return g.seed }},
+		{Format: "God mode: %v", Value: func(g *Game) interface{} { return g.godMode }},
+		{Format: "Time scale: %.2f", Value: func(g *Game) interface{} { return g.timeScale }},
+		{Format: "Camera zoom: %.3fx", Value: func(g *Game) interface{} { return g.camera.pose.zoom }},
+	}
+	for i := range lines {
+		lines[i].X = debugPanelX + 10
+		lines[i].Y = debugPanelY + 8 + float64(i*debugLineSpacing)
+		lines[i].Scale = 1.5
+	}
+	return NewHUD(lines).AnchorAll(AnchorRight)
+}
@@ -1,46 +1,59 @@
 package main
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
 
 // --- Structs ---
 
 // Obstacle defines a scrolling hazard
 type Obstacle struct {
 	x, y, width, height float64
+	prevX               float64 // x before the latest simulation step (for render interpolation)
 	passed              bool // Track if this obstacle has been passed for scoring
+	anim                AnimationPlayer // Kelp wave animation
 }
 
 // Coin represents a collectible coin
 type Coin struct {
 	x, y, size float64
+	prevX      float64 // x before the latest simulation step
 	collected  bool
+	anim       AnimationPlayer // Spin animation
 }
 
 // Fish represents a follower fish that trails behind the leader
 type Fish struct {
 	x, y           float64 // Current position of the fish
+	prevX, prevY   float64 // Position before the latest simulation step
 	offsetX, offsetY float64 // Base relative offset from the leader's position (center of wander circle)
 	targetOffsetX, targetOffsetY float64 // Random target offset for wandering
 	wanderTimer    int     // Timer to change wander target
 	wanderInterval int     // Random interval for this fish to wander
+	anim           AnimationPlayer // Swim cycle
+}
+
+// Jellyfish is a hazard that drifts with the current while bobbing up and down
+type Jellyfish struct {
+	x, y         float64 // Top-left corner
+	prevX, prevY float64 // Position before the latest simulation step
+	baseY        float64 // Center of the bobbing motion
+	size         float64
+	phase        float64 // Bobbing phase in radians
 }
 
 // BackgroundFish represents ambient fish swimming in the background
 type BackgroundFish struct {
 	x, y       float64 // Current position
+	prevX, prevY float64 // Position before the latest simulation step
 	speed      float64 // Swimming speed
 	direction  int     // 1 for right, -1 for left
 	size       float64 // Size of the fish
 	depth      float64 // Depth factor (0.0 to 1.0, lower = further back)
-}
-
-// Bubble represents a bubble floating upward
-type Bubble struct {
-	x, y       float64 // Current position
-	speed      float64 // Rising speed
-	size       float64 // Size of the bubble
-	wobble     float64 // Horizontal wobble offset
-	wobbleSpeed float64 // Speed of wobble animation
+	layer      int     // Parallax layer the fish swims in (drifts with that layer's scroll)
+	anim       AnimationPlayer // Swim cycle
 }
 
 // Difficulty levels
@@ -56,24 +69,58 @@ const (
 // Game holds the entire game state
 type Game struct {
 	playerY    float64
+	prevPlayerY float64 // playerY before the latest simulation step
 	obstacles  []*Obstacle
 	coins      []*Coin // Array of coins
+	jellyfish  []*Jellyfish // Drifting jellyfish hazards
 	fish       []*Fish // Array of follower fish
 	backgroundFish []*BackgroundFish // Array of background ambient fish
-	bubbles    []*Bubble // Array of floating bubbles
+	effects    *particleEffects // Ambient bubbles, wake, sparkles, bursts and debris
+	camera     *Camera // View of the world: follows the leader, zooms with speed and shakes on collisions
+	parallaxLayers []*ParallaxLayer // Scenery layers scrolling at fractions of the scroll speed
+	obstacleIndex broadPhase // Kelp indexed by x for collision queries (rebuilt every step)
+	coinIndex     broadPhase // Coins indexed by x for collection queries
 	score      int     // Score based on obstacles passed
 	coinsCollected int // Number of coins collected
 	gameOver   bool
 	gameStarted bool   // Whether the game has started (after difficulty selection)
 	difficulty Difficulty // Selected difficulty level
 	spawnTimer int
-	restartInput string // Input string for restart code
-	gameTime   int     // Total frames elapsed (for speed increase)
+	gameTime   int     // Total simulation steps elapsed (for speed increase)
 	speedMultiplier float64 // Current speed multiplier
+	settings *Settings   // Persisted preferences (shared across restarts)
+	input    *Input      // Action-based input layer built from the settings' bindings
+	audio    *AudioManager // Sound effects and music (shared across restarts)
+	controls *controlsScreen // Key rebinding screen (nil when closed)
+	settingsMenu *settingsScreen // Settings screen (nil when closed); the controls screen can open over it
+	paused   bool        // Whether the simulation is paused
+	showCollisionShapes bool // Collision debug view (F4)
+	showDebug bool           // Hitbox and stats overlay (F3)
+	console  *console        // Developer console (kept across restarts)
+	godMode  bool            // Collisions with hazards are ignored (console "god")
+	cheated  bool            // A console command changed the run, so it's ranked with assisted runs
+	previousBest int         // Best score in this run's ranking before it was recorded
+	timeScale float64        // Simulation speed relative to real time (console "timescale")
+	screenshotRequested bool // Save the next drawn frame (console "screenshot")
+	accumulator float64  // Simulation steps owed but not yet run (fraction = interpolation factor)
+	seed     int64       // Seed of rng, enough to replay the run with the same inputs
+	rng      *rand.Rand  // Generator for everything that affects play
+	formation Formation  // Current school formation
+	clusterOffsets [][2]float64 // Randomly generated base offsets for the cluster formation
+	pack     *AssetPack  // Active asset pack (sprites and palette)
+	colors   PackColors  // Pack palette with the accessibility settings applied; used for all drawing
 	// Sprites
-	fishSprite    *ebiten.Image // Pixel art sprite for fish
-	kelpSprite    *ebiten.Image // Pixel art sprite for kelp (will be scaled)
+	fishAtlas     *SpriteAtlas  // Fish frames and the "swim" animation
+	kelpAtlas     *SpriteAtlas  // Kelp frames and the "wave" animation (will be scaled)
+	leaderAnim    AnimationPlayer // Leader's swim cycle
 	gameOverImage *ebiten.Image // Optional image to display on game over screen
+	// HUDs and screens
+	statsHUD    *HUD // Score / Coins / Speed readout
+	menuUI      *UI  // Difficulty selection menu
+	pauseUI     *UI  // Pause overlay
+	gameOverUI  *UI  // Game over panel
+	collisionDebugHUD *HUD // Label of the collision debug view
+	debugHUD    *HUD // Stats panel of the debug overlay
 }
 
 // A simple structure to represent a bounding box for collision checking
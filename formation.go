@@ -0,0 +1,96 @@
+package main
+
+import "math"
+
+// --- School Formations ---
+
+// Formation is an arrangement of the follower fish's base offsets around the leader
+type Formation int
+
+const (
+	FormationCluster Formation = iota // Random cluster in a circle behind the leader
+	FormationWedge                    // V shape trailing back from the leader
+	FormationColumns                  // Three tight rows behind the leader
+	numFormations
+)
+
+// formationNames are the message keys of the names shown on the HUD
+var formationNames = [numFormations]string{
+	FormationCluster: "formation.cluster",
+	FormationWedge:   "formation.wedge",
+	FormationColumns: "formation.columns",
+}
+
+func (f Formation) String() string {
+	return tr(formationNames[f])
+}
+
+// formationOffset returns the base offset (relative to the leader's top-left
+// corner) of the i-th follower in the formation
+func (g *Game) formationOffset(f Formation, i int) (float64, float64) {
+	// Offset that lines a follower's center up with the leader's center
+	centerX := float64(PlayerSize/2 - FishSize/2)
+	centerY := float64(PlayerSize/2 - FishSize/2)
+
+	switch f {
+	case FormationWedge:
+		// Alternate between the upper and lower arm, one rank further back each pair
+		rank := float64(i/2 + 1)
+		side := 1.0
+		if i%2 == 0 {
+			side = -1.0
+		}
+		return centerX - 32*rank, centerY + side*26*rank
+	case FormationColumns:
+		row := float64(i%3 - 1)
+		col := float64(i / 3)
+		return centerX - 70 - 40*col, centerY + row*55
+	default:
+		return g.clusterOffsets[i][0], g.clusterOffsets[i][1]
+	}
+}
+
+// cycleFormation switches the school to the next formation; the fish swim to
+// their new places using the normal following behavior
+func (g *Game) cycleFormation() {
+	g.formation = (g.formation + 1) % numFormations
+	for i, fish := range g.fish {
+		fish.offsetX, fish.offsetY = g.formationOffset(g.formation, i)
+		fish.targetOffsetX = fish.offsetX
+		fish.targetOffsetY = fish.offsetY
+	}
+}
+
+// addFollower adds a fish to the school at its place in the current formation.
+// Its cluster offset is picked at random within the cluster circle.
+func (g *Game) addFollower() {
+	angle := g.rng.Float64() * 2 * math.Pi
+	radius := CircleRadius * math.Sqrt(g.rng.Float64())
+	g.clusterOffsets = append(g.clusterOffsets, [2]float64{
+		CircleOffsetX + radius*math.Cos(angle),
+		radius * math.Sin(angle),
+	})
+
+	offsetX, offsetY := g.formationOffset(g.formation, len(g.fish))
+	fish := &Fish{
+		x:              PlayerX + offsetX,
+		y:              g.playerY + offsetY,
+		offsetX:        offsetX,
+		offsetY:        offsetY,
+		targetOffsetX:  offsetX,
+		targetOffsetY:  offsetY,
+		wanderInterval: FishWanderIntervalMin + g.rng.Intn(FishWanderIntervalMax-FishWanderIntervalMin+1),
+		anim:           newAnimationPlayer(g.fishAtlas.Animation("swim")),
+	}
+	fish.prevX, fish.prevY = fish.x, fish.y
+	g.fish = append(g.fish, fish)
+}
+
+// removeFollowers removes up to n fish from the back of the school
+func (g *Game) removeFollowers(n int) {
+	if n > len(g.fish) {
+		n = len(g.fish)
+	}
+	g.fish = g.fish[:len(g.fish)-n]
+	g.clusterOffsets = g.clusterOffsets[:len(g.fish)]
+}
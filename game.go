@@ -1,29 +1,37 @@
 package main
 
 import (
-	"fmt"
-	"image/color"
 	"math"
 	"math/rand"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"github.com/hajimehoshi/bitmapfont/v4"
 )
 
 // --- Initialization ---
 
 func init() {
-	// Seed the random number generator for obstacle generation
+	// Seed the shared generator used for cosmetic effects (bubbles, background fish)
 	rand.Seed(time.Now().UnixNano())
 }
 
-// NewGame initializes the game state
-func NewGame() *Game {
+// NewGame initializes the game state with a fresh random seed
+func NewGame(settings *Settings) *Game {
+	return NewSeededGame(settings, time.Now().UnixNano())
+}
+
+// NewSeededGame initializes the game state. Everything that affects play (the
+// school's starting offsets, wandering, obstacles and coins) is drawn from a
+// generator seeded with seed, so the same seed and inputs replay the same run.
+func NewSeededGame(settings *Settings, seed int64) *Game {
 	centerY := float64(ScreenHeight)/2 - PlayerSize/2
+	rng := rand.New(rand.NewSource(seed))
+	
+	// Load the active asset pack (fish swim cycle, kelp wave, palette)
+	pack := loadActivePack(settings.AssetPack)
+	swim := pack.fishAtlas.Animation("swim")
+	colors := sceneColors(pack, settings) // Pack palette with the accessibility settings applied
 	
 	// Initialize fish array - place them randomly in a circle behind the leader
 	fish := make([]*Fish, NumFish)
@@ -31,6 +39,7 @@ func NewGame() *Game {
 	circleCenterY := centerY
 	
 	// Place fish randomly within circle, avoiding overlaps
+	clusterOffsets := make([][2]float64, NumFish)
 	maxAttempts := 100
 	for i := 0; i < NumFish; i++ {
 		var fx, fy float64
@@ -38,9 +47,9 @@ func NewGame() *Game {
 		
 		for attempt := 0; attempt < maxAttempts && !placed; attempt++ {
 			// Random angle and distance within the circle
-			angle := rand.Float64() * 2 * math.Pi
+			angle := rng.Float64() * 2 * math.Pi
 			// Use square root to get uniform distribution within circle
-			radius := CircleRadius * math.Sqrt(rand.Float64())
+			radius := CircleRadius * math.Sqrt(rng.Float64())
 			
 			fx = circleCenterX + radius*math.Cos(angle)
 			fy = circleCenterY + radius*math.Sin(angle)
@@ -65,6 +74,7 @@ func NewGame() *Game {
 		// Store relative offset from leader's center
 		baseOffsetX := fx - PlayerX
 		baseOffsetY := fy - centerY
+		clusterOffsets[i] = [2]float64{baseOffsetX, baseOffsetY} // Kept so the cluster formation can be restored
 		fish[i] = &Fish{
 			x:             fx,
 			y:             fy,
@@ -72,8 +82,9 @@ func NewGame() *Game {
 			offsetY:       baseOffsetY,
 			targetOffsetX: baseOffsetX,
 			targetOffsetY: baseOffsetY,
-			wanderTimer:   rand.Intn(FishWanderIntervalMax), // Random start time
-			wanderInterval: FishWanderIntervalMin + rand.Intn(FishWanderIntervalMax-FishWanderIntervalMin+1), // Random interval for this fish
+			wanderTimer:   rng.Intn(FishWanderIntervalMax), // Random start time
+			wanderInterval: FishWanderIntervalMin + rng.Intn(FishWanderIntervalMax-FishWanderIntervalMin+1), // Random interval for this fish
+			anim:          newAnimationPlayer(swim),
 		}
 	}
 	
@@ -81,7 +92,7 @@ func NewGame() *Game {
 	backgroundFish := make([]*BackgroundFish, NumBackgroundFish)
 	for i := 0; i < NumBackgroundFish; i++ {
 		// Random starting position
-		startX := rand.Float64() * ScreenWidth
+		startX := rand.Float64() * display.worldWidth()
 		startY := rand.Float64() * ScreenHeight
 		
 		// Random direction (1 for right, -1 for left)
@@ -106,42 +117,11 @@ func NewGame() *Game {
 			direction: direction,
 			size:      size,
 			depth:     depth,
+			layer:     layerForDepth(depth),
+			anim:      newAnimationPlayer(swim),
 		}
 	}
 	
-	// Initialize bubbles - floating upward at various speeds
-	bubbles := make([]*Bubble, NumBubbles)
-	for i := 0; i < NumBubbles; i++ {
-		// Random starting position
-		startX := rand.Float64() * ScreenWidth
-		startY := rand.Float64() * ScreenHeight
-		
-		// Random rising speed (slower bubbles)
-		speed := 0.5 + rand.Float64()*1.5 // 0.5 to 2.0 pixels per frame
-		
-		// Random size (smaller bubbles)
-		size := 3.0 + rand.Float64()*8.0 // 3-11 pixels
-		
-		// Random wobble speed for horizontal movement
-		wobbleSpeed := 0.02 + rand.Float64()*0.03 // 0.02 to 0.05
-		
-		bubbles[i] = &Bubble{
-			x:           startX,
-			y:           startY,
-			speed:       speed,
-			size:        size,
-			wobble:      rand.Float64() * 2 * math.Pi, // Random starting phase
-			wobbleSpeed: wobbleSpeed,
-		}
-	}
-	
-	// Load optional game over image (won't crash if it doesn't exist)
-	gameOverImg, err := loadImageFromFile("assets/anay.png")
-	if err != nil {
-		// Image not found or couldn't load - that's okay, just use nil
-		gameOverImg = nil
-	}
-	
 	g := &Game{
 		// Center the player vertically on the left side
 		playerY: centerY,
@@ -149,109 +129,168 @@ func NewGame() *Game {
 		coins:      make([]*Coin, 0),
 		fish:       fish,
 		backgroundFish: backgroundFish,
-		bubbles:    bubbles,
+		effects:    newParticleEffects(&colors),
+		camera:     newCamera(),
+		parallaxLayers: newParallaxLayers(&colors),
 		score:      0,
 		coinsCollected: 0,
 		gameOver:   false,
 		gameStarted: false, // Start with difficulty selection
 		difficulty: DifficultyNone,
 		spawnTimer: 0,
-		restartInput: "",
 		gameTime:   0,
 		speedMultiplier: 1.0,
-		fishSprite: createFishSprite(),
-		kelpSprite: createKelpSprite(),
-		gameOverImage: gameOverImg,
-	}
+		settings:   settings,
+		input:      NewInput(settings),
+		audio:      loadAudio(settings),
+		pack:       pack,
+		colors:     colors,
+		formation:  FormationCluster,
+		clusterOffsets: clusterOffsets,
+		fishAtlas:  pack.fishAtlas,
+		kelpAtlas:  kelpAtlasFor(pack, colors),
+		leaderAnim: newAnimationPlayer(swim),
+		gameOverImage: pack.gameOverImage, // Optional (nil if the pack has none)
+		statsHUD:   newStatsHUD(),
+		menuUI:     newDifficultyMenuUI(),
+		pauseUI:    newPauseUI(),
+		gameOverUI: newGameOverUI(),
+		collisionDebugHUD: newCollisionDebugHUD(),
+		debugHUD:   newDebugHUD(),
+		console:    &console{},
+		timeScale:  1.0,
+		seed:       seed,
+		rng:        rng,
+	}
+	g.savePreviousPositions() // Nothing has moved yet, so Draw shows the spawn positions
 	return g
 }
 
+// restart starts over from the difficulty menu, keeping the developer console's output and history
+func (g *Game) restart() {
+	c := g.console
+	*g = *NewGame(g.settings)
+	g.console = c
+}
+
 // --- Ebitengine Interface Implementations ---
 
 func (g *Game) Update() error {
+	// Poll input devices (gamepads can be connected or removed at any time)
+	g.input.Update()
+	
+	// Mix the music for how far into the run we are
+	g.audio.UpdateMusic(g.musicIntensity())
+	
+	// The developer console takes all keyboard input while it's open
+	if g.updateConsole() {
+		return nil
+	}
+	
+	// Debug views: F3 shows hitboxes and simulation stats, F4 compares collision shapes
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		g.showDebug = !g.showDebug
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF4) {
+		g.showCollisionShapes = !g.showCollisionShapes
+	}
+	
+	// Controls screen is opened from the difficulty menu and covers it
+	if g.controls != nil {
+		g.updateControlsScreen()
+		return nil
+	}
+	
+	// Settings screen is also opened from the difficulty menu
+	if g.settingsMenu != nil {
+		g.updateSettingsScreen()
+		return nil
+	}
+	
+	// A touch means there's no keyboard to steer with, so switch to pointer steering
+	if g.input.TouchBegan() && !g.settings.PointerSteering {
+		g.settings.PointerSteering = true
+		g.saveSettings()
+	}
+	
 	// Handle difficulty selection before game starts
 	if !g.gameStarted {
-		// Check for difficulty selection keys
-		if inpututil.IsKeyJustPressed(ebiten.Key1) || inpututil.IsKeyJustPressed(ebiten.KeyE) {
-			g.difficulty = DifficultyEasy
-			g.gameStarted = true
-		} else if inpututil.IsKeyJustPressed(ebiten.Key2) || inpututil.IsKeyJustPressed(ebiten.KeyM) {
-			g.difficulty = DifficultyMedium
-			g.gameStarted = true
-		} else if inpututil.IsKeyJustPressed(ebiten.Key3) || inpututil.IsKeyJustPressed(ebiten.KeyH) {
-			g.difficulty = DifficultyHard
-			g.gameStarted = true
-		}
+		g.menuUI.Update(g)
 		return nil
 	}
 	
 	if g.gameOver {
-		// Handle text input for restart code "anay"
-		// Check for Enter key to submit
-		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-			if g.restartInput == "anay" {
-				*g = *NewGame()
-			} else {
-				// Wrong code, clear input
-				g.restartInput = ""
-			}
-			return nil
-		}
-		
-		// Handle backspace
-		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
-			if len(g.restartInput) > 0 {
-				g.restartInput = g.restartInput[:len(g.restartInput)-1]
-			}
+		// Gamepads can't type the restart code, so the gamepad Confirm button restarts directly
+		if g.input.gamepad.justPressed(ActionConfirm) {
+			g.restart()
 			return nil
 		}
 		
-		// Capture typed characters (letters only, lowercase)
-		keys := inpututil.AppendJustPressedKeys(nil)
-		for _, key := range keys {
-			// Convert key to character (a-z only)
-			if key >= ebiten.KeyA && key <= ebiten.KeyZ {
-				char := string(rune('a' + (key - ebiten.KeyA)))
-				g.restartInput += char
-				// Limit input length to prevent overflow
-				if len(g.restartInput) > 10 {
-					g.restartInput = g.restartInput[:10]
-				}
-			}
-		}
+		// The scene holds still, but the crash's particles play out
+		g.advanceSimulation()
 		
+		// Type 'anay' and press Enter to restart (see newGameOverUI)
+		g.gameOverUI.Update(g)
+		return nil
+	}
+
+	// While shown, M switches the collision mode
+	if g.showCollisionShapes && inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		g.toggleCollisionMode()
+	}
+
+	// Pause toggles with the Pause key; Back also resumes
+	if g.input.JustPressed(ActionPause) || (g.paused && g.input.JustPressed(ActionBack)) {
+		g.paused = !g.paused
+	}
+	if g.paused {
+		g.pauseUI.Update(g)
 		return nil
 	}
+	
+	// Switch school formation
+	if g.input.JustPressed(ActionCycleFormation) {
+		g.cycleFormation()
+	}
 
+	// Run the fixed-timestep simulation for the time this Update covers
+	g.advanceSimulation()
+
+	return nil
+}
+
+// step advances the simulation by one fixed timestep (1/SimTPS seconds).
+// Speeds and timers below are per step.
+func (g *Game) step() {
 	// 0. Update game time and speed multiplier
 	g.gameTime++
 	
 	// Calculate speed multiplier based on difficulty
-	var accelerationRate float64
-	switch g.difficulty {
-	case DifficultyEasy:
-		accelerationRate = 8000.0 // Slow acceleration (increased from 12000)
-	case DifficultyMedium:
-		accelerationRate = 4000.0 // Medium acceleration (increased from 5000)
-	case DifficultyHard:
-		accelerationRate = 2000.0 // Fast acceleration (increased from 2500)
-	default:
-		accelerationRate = 8000.0 // Default to easy
-	}
-	
-	g.speedMultiplier = 2.0 + float64(g.gameTime)/accelerationRate
-	// Cap the maximum speed multiplier at 5.0 (5x original speed, increased from 3.0)
-	if g.speedMultiplier > 5.0 {
-		g.speedMultiplier = 5.0
+	g.speedMultiplier = 2.0 + float64(g.gameTime)/g.accelerationRate()
+	// Cap the maximum speed multiplier at 5.0 (5x original speed, increased from 3.0), or lower with assist mode
+	if maxSpeed := g.settings.Assist.active().SpeedCap; g.speedMultiplier > maxSpeed {
+		g.speedMultiplier = maxSpeed
 	}
+	currentScrollSpeed := ScrollSpeed * g.speedMultiplier
 
 	// 1. Handle Player Input
-	if ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
+	// Keys and the D-pad move at full speed; otherwise the analog stick sets
+	// the vertical velocity proportionally (up to PlayerSpeed)
+	moveUp := g.input.Pressed(ActionMoveUp)
+	moveDown := g.input.Pressed(ActionMoveDown)
+	if moveUp {
 		g.playerY -= PlayerSpeed
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
+	if moveDown {
 		g.playerY += PlayerSpeed
 	}
+	if !moveUp && !moveDown {
+		if g.settings.PointerSteering {
+			g.steerTowardPointer()
+		} else {
+			g.playerY += g.input.VerticalAxis() * PlayerSpeed
+		}
+	}
 
 	// Clamp playerY within the screen bounds
 	if g.playerY < 0 {
@@ -262,19 +301,22 @@ func (g *Game) Update() error {
 	}
 
 	// 2. Update Fish Positions (following behavior with random wandering)
+	g.leaderAnim.Update()
 	for _, fish := range g.fish {
+		fish.anim.Update()
+		
 		// Update wander timer and pick new random target when timer expires
 		fish.wanderTimer++
 		if fish.wanderTimer >= fish.wanderInterval {
 			fish.wanderTimer = 0
 			
 			// Pick a new random wander interval for next time (adds variety to movement)
-			fish.wanderInterval = FishWanderIntervalMin + rand.Intn(FishWanderIntervalMax-FishWanderIntervalMin+1)
+			fish.wanderInterval = FishWanderIntervalMin + g.rng.Intn(FishWanderIntervalMax-FishWanderIntervalMin+1)
 			
 			// Pick a new random target offset within the wander radius
 			// Use random angle and distance from base offset
-			angle := rand.Float64() * 2 * math.Pi
-			radius := FishWanderRadius * math.Sqrt(rand.Float64())
+			angle := g.rng.Float64() * 2 * math.Pi
+			radius := FishWanderRadius * math.Sqrt(g.rng.Float64())
 			
 			fish.targetOffsetX = fish.offsetX + radius*math.Cos(angle)
 			fish.targetOffsetY = fish.offsetY + radius*math.Sin(angle)
@@ -313,63 +355,59 @@ func (g *Game) Update() error {
 			fish.x = 0
 		}
 		if fish.x > ScreenWidth-FishSize {
-			fish.x = float64(ScreenWidth - FishSize)
+			fish.x = ScreenWidth - FishSize
 		}
 	}
 
+	// 2.4. Scroll Parallax Layers
+	for _, layer := range g.parallaxLayers {
+		layer.update(currentScrollSpeed)
+	}
+
 	// 2.5. Update Background Fish Positions
 	for _, bgFish := range g.backgroundFish {
-		// Move fish in their direction
+		bgFish.anim.Update()
+		
+		// Move fish in their direction, drifting back with the layer they swim in
 		bgFish.x += bgFish.speed * float64(bgFish.direction)
+		bgFish.x -= currentScrollSpeed * g.parallaxLayers[bgFish.layer].speedFactor
 		
-		// Wrap around when fish goes off screen
-		if bgFish.direction > 0 && bgFish.x > ScreenWidth+bgFish.size {
+		// Wrap around when fish goes off screen (either side, since the layer
+		// drift can carry a right-swimming fish off the left edge)
+		if bgFish.x > display.worldWidth()+bgFish.size {
 			// Moving right, wrap to left
 			bgFish.x = -bgFish.size
 			bgFish.y = rand.Float64() * ScreenHeight
-		} else if bgFish.direction < 0 && bgFish.x < -bgFish.size {
+			bgFish.prevX, bgFish.prevY = bgFish.x, bgFish.y // Don't interpolate across the jump
+		} else if bgFish.x < -bgFish.size {
 			// Moving left, wrap to right
-			bgFish.x = ScreenWidth + bgFish.size
+			bgFish.x = display.worldWidth() + bgFish.size
 			bgFish.y = rand.Float64() * ScreenHeight
+			bgFish.prevX, bgFish.prevY = bgFish.x, bgFish.y
 		}
 	}
 
-	// 2.6. Update Bubble Positions
-	for _, bubble := range g.bubbles {
-		// Move bubble upward
-		bubble.y -= bubble.speed
-		
-		// Apply wobble (horizontal sway)
-		bubble.wobble += bubble.wobbleSpeed
-		wobbleOffset := math.Sin(bubble.wobble) * 10.0 // Sway 10 pixels left/right
-		bubble.x += wobbleOffset * 0.05 // Apply small amount each frame
-		
-		// Wrap around when bubble goes off top of screen
-		if bubble.y < -bubble.size {
-			// Reset to bottom with new random x position
-			bubble.y = ScreenHeight + bubble.size
-			bubble.x = rand.Float64() * ScreenWidth
-			bubble.wobble = rand.Float64() * 2 * math.Pi
-		}
-		
-		// Keep bubble within horizontal bounds (with some slack for wobble)
-		if bubble.x < -20 {
-			bubble.x = ScreenWidth + 20
-		} else if bubble.x > ScreenWidth+20 {
-			bubble.x = -20
-		}
-	}
+	// 2.6. Update Particles (ambient bubbles, the leader's wake, sparkles and bursts)
+	g.effects.update(currentScrollSpeed, g.settings)
+	g.emitWake()
+
+	// 2.7. Follow the leader with the camera, zooming out as the speed climbs
+	g.updateCamera()
 
 	// 3. Move and Cleanup Obstacles, Update Score
-	currentScrollSpeed := ScrollSpeed * g.speedMultiplier
+	passed := false // Top and bottom kelp pass together; chime once
 	newObstacles := make([]*Obstacle, 0)
 	for _, obs := range g.obstacles {
 		obs.x -= currentScrollSpeed // Scroll left with speed multiplier
+		if !g.settings.ReducedMotion {
+			obs.anim.Update() // Reduced motion holds the kelp still
+		}
 		
 		// Check if obstacle has been passed (player has passed it)
 		if !obs.passed && obs.x+obs.width < PlayerX {
 			obs.passed = true
 			g.score++ // Increment score when obstacle is passed
+			passed = true
 		}
 		
 		if obs.x > -obs.width {
@@ -377,11 +415,15 @@ func (g *Game) Update() error {
 		}
 	}
 	g.obstacles = newObstacles
+	if passed {
+		g.audio.Play(SoundPass)
+	}
 
 	// 4. Move and Cleanup Coins
 	newCoins := make([]*Coin, 0)
 	for _, coin := range g.coins {
 		coin.x -= currentScrollSpeed // Scroll left with speed multiplier
+		coin.anim.Update()
 		
 		// Remove coins that are off-screen or collected
 		if coin.x > -coin.size && !coin.collected {
@@ -390,42 +432,37 @@ func (g *Game) Update() error {
 	}
 	g.coins = newCoins
 
+	// 4.25. Move and Cleanup Jellyfish (they bob up and down as they drift)
+	newJellyfish := make([]*Jellyfish, 0, len(g.jellyfish))
+	for _, jelly := range g.jellyfish {
+		jelly.x -= currentScrollSpeed * JellyfishDriftFactor
+		jelly.phase += JellyfishBobSpeed
+		jelly.y = jelly.baseY + math.Sin(jelly.phase)*JellyfishBobHeight
+		if jelly.x > -jelly.size {
+			newJellyfish = append(newJellyfish, jelly)
+		}
+	}
+	g.jellyfish = newJellyfish
+
+	// 4.5. Index kelp and coins by x so the checks below only test nearby ones
+	g.buildCollisionIndexes()
+
 	// 5. Collision Detection for Leader with Obstacles
 	// Use circle-based collision for fish (more accurate than rectangle)
 	playerCircle := circleCollision{
 		x:      PlayerX + PlayerSize/2,
 		y:      g.playerY + PlayerSize/2,
-		radius: PlayerSize * 0.35, // Use 40% of size as radius for tighter fit
+		radius: leaderCollisionRadius, // Use 35% of size as radius for tighter fit
 	}
 
-	for _, obs := range g.obstacles {
-		obsRect := collisionRect{
-			x: obs.x,
-			y: obs.y,
-			w: obs.width,
-			h: obs.height,
-		}
-		if checkCircleRectCollision(playerCircle, obsRect) {
-			g.gameOver = true
-			break
-		}
+	// Swept from where the leader was last step, so fast kelp can't slip through between steps
+	if kelp := g.hitsObstacle(PlayerX, g.playerY, PlayerX, g.prevPlayerY, PlayerSize, leaderCollisionRadius); kelp || g.hitsJellyfish(playerCircle) {
+		g.collide(playerCircle.x, playerCircle.y, kelp)
 	}
 
 	// 6. Coin Collection Detection for Leader
 	if !g.gameOver {
-		for _, coin := range g.coins {
-			if !coin.collected {
-				coinCircle := circleCollision{
-					x:      coin.x + coin.size/2,
-					y:      coin.y + coin.size/2,
-					radius: coin.size * 0.4,
-				}
-				if checkCircleCollision(playerCircle, coinCircle) {
-					coin.collected = true
-					g.coinsCollected++
-				}
-			}
-		}
+		g.collectCoins(playerCircle)
 	}
 
 	// 7. Collision Detection for all Fish with Obstacles
@@ -434,86 +471,130 @@ func (g *Game) Update() error {
 			fishCircle := circleCollision{
 				x:      fish.x + FishSize/2,
 				y:      fish.y + FishSize/2,
-				radius: FishSize * 0.4, // Use 40% of size as radius for tighter fit
-			}
-			
-			for _, obs := range g.obstacles {
-				obsRect := collisionRect{
-					x: obs.x,
-					y: obs.y,
-					w: obs.width,
-					h: obs.height,
-				}
-				if checkCircleRectCollision(fishCircle, obsRect) {
-					g.gameOver = true
-					break
-				}
+				radius: followerCollisionRadius,
 			}
-			if g.gameOver {
+			if kelp := g.hitsObstacle(fish.x, fish.y, fish.prevX, fish.prevY, FishSize, followerCollisionRadius); kelp || g.hitsJellyfish(fishCircle) {
+				g.collide(fishCircle.x, fishCircle.y, kelp)
 				break
 			}
 		}
 	}
 
+	if g.gameOver {
+		g.audio.Play(SoundCollision)
+		g.recordRun()
+	}
+
 	// 8. Coin Collection Detection for Fish
 	if !g.gameOver {
 		for _, fish := range g.fish {
 			fishCircle := circleCollision{
 				x:      fish.x + FishSize/2,
 				y:      fish.y + FishSize/2,
-				radius: FishSize * 0.4,
-			}
-			
-			for _, coin := range g.coins {
-				if !coin.collected {
-					coinCircle := circleCollision{
-						x:      coin.x + coin.size/2,
-						y:      coin.y + coin.size/2,
-						radius: coin.size * 0.4,
-					}
-					if checkCircleCollision(fishCircle, coinCircle) {
-						coin.collected = true
-						g.coinsCollected++
-					}
-				}
+				radius: followerCollisionRadius,
 			}
+			g.collectCoins(fishCircle)
 		}
 	}
 
 	// 9. Spawn New Obstacles
 	g.spawnTimer++
-	// Spawn a new set of obstacles every 150 frames (approx. 2.5 seconds)
+	// Spawn a new set of obstacles every 150 steps (2.5 seconds)
 	if g.spawnTimer >= 150 {
 		g.spawnTimer = 0
 		g.spawnObstaclePair()
 	}
-
-	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	// Draw the background
-	screen.Fill(color.RGBA{135, 206, 250, 255}) // Sky Blue (Water/Air)
+	// Everything is drawn in logical pixels, then scaled to the window
+	canvas := display.target()
+	g.drawScene(canvas)
+
+	// Screenshots are taken before the console is drawn so it doesn't cover the game
+	if g.screenshotRequested {
+		g.screenshotRequested = false
+		if name, err := saveScreenshot(canvas); err != nil {
+			g.console.print("Screenshot failed: " + err.Error())
+		} else {
+			g.console.print("Saved " + name)
+		}
+	}
+	if g.console.open {
+		g.drawConsole(canvas)
+	}
+	display.present(screen)
+}
 
-	// Draw Background Fish (drawn first so they appear behind everything)
-	for _, bgFish := range g.backgroundFish {
-		g.drawBackgroundFish(screen, bgFish)
+// drawScene draws the menu or the game, with whatever overlays are active
+func (g *Game) drawScene(screen *ebiten.Image) {
+	// Draw the world through the camera (the water shows at the edges while it shakes)
+	world := display.worldTarget()
+	g.drawWorld(world)
+	screen.Fill(g.colors.Water)
+	g.camera.draw(screen, world, g.stepAlpha())
+
+	// Everything below is in screen space, so the camera doesn't move it
+
+	// If game hasn't started, show difficulty selection menu
+	if !g.gameStarted {
+		if g.controls != nil {
+			g.drawControlsScreen(screen)
+		} else if g.settingsMenu != nil {
+			g.drawSettingsScreen(screen)
+		} else {
+			g.menuUI.Draw(screen, g)
+		}
+		return
 	}
 
-	// Draw Bubbles (in the background layer)
-	for _, bubble := range g.bubbles {
-		g.drawBubble(screen, bubble)
+	// Draw the collision debug view's legend and the debug stats
+	if g.showCollisionShapes {
+		g.collisionDebugHUD.Draw(screen, g)
+	}
+	if g.showDebug {
+		g.drawDebugStats(screen)
 	}
 
-	// If game hasn't started, show difficulty selection menu
+	// Draw Score, Coin Count, and Speed (larger text)
+	g.statsHUD.Draw(screen, g)
+	
+	// Draw Pause Overlay
+	if g.paused && !g.gameOver {
+		g.pauseUI.Draw(screen, g)
+	}
+
+	// Draw Game Over Screen
+	if g.gameOver {
+		g.gameOverUI.Draw(screen, g)
+	}
+}
+
+// drawWorld draws the scenery and, once a run has started, everything in it
+func (g *Game) drawWorld(screen *ebiten.Image) {
+	// Draw the background
+	screen.Fill(g.colors.Water) // Sky Blue (Water/Air) in the default pack
+
+	// Draw Parallax Scenery and Background Fish (drawn first so they appear behind everything)
+	g.drawBackgroundLayers(screen)
+
+	// Draw Bubbles (in the background layer; purely decorative, so hidden with reduced effects)
+	if !g.settings.ReducedEffects {
+		g.effects.bubbles.draw(screen, g.stepAlpha())
+	}
+
+	// The menu shows only the scenery
 	if !g.gameStarted {
-		g.drawDifficultyMenu(screen)
+		g.drawForegroundLayers(screen)
 		return
 	}
 
+	// Positions are drawn partway between the last two simulation steps so
+	// motion stays smooth when Update runs faster than the simulation
+
 	// Draw Obstacles (Kelp)
 	for _, obs := range g.obstacles {
-		g.drawKelp(screen, obs.x, obs.y, obs.width, obs.height)
+		g.drawKelp(screen, obs.anim.Frame(), g.interpolate(obs.prevX, obs.x), obs.y, obs.width, obs.height)
 	}
 
 	// Draw Coins
@@ -523,160 +604,56 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 	}
 
+	// Draw Jellyfish
+	for _, jelly := range g.jellyfish {
+		g.drawJellyfish(screen, jelly)
+	}
+
+	// Draw the leader's wake (behind the school)
+	g.effects.wake.draw(screen, g.stepAlpha())
+	
 	// Draw Player (The Leader)
-	g.drawFish(screen, PlayerX, g.playerY, PlayerSize, true)
+	g.drawFish(screen, g.leaderAnim.Frame(), PlayerX, g.interpolate(g.prevPlayerY, g.playerY), PlayerSize, true)
 	
 	// Draw all following fish
 	for _, fish := range g.fish {
-		g.drawFish(screen, fish.x, fish.y, FishSize, false)
+		g.drawFish(screen, fish.anim.Frame(), g.interpolate(fish.prevX, fish.x), g.interpolate(fish.prevY, fish.y), FishSize, false)
 	}
 
-	// Draw Score, Coin Count, and Speed (larger text)
-	scoreText := fmt.Sprintf("Score: %d", g.score)
-	coinText := fmt.Sprintf("Coins: %d", g.coinsCollected)
-	speedText := fmt.Sprintf("Speed: %.2fx", g.speedMultiplier)
-	
-	// Use bitmap font with scaling for larger, more readable text
-	statsFace := text.NewGoXFace(bitmapfont.Face)
-	statsColor := color.White
-	
-	// Draw Score
-	scoreOpts := &text.DrawOptions{}
-	scoreOpts.GeoM.Scale(2.0, 2.0) // 2x scale
-	scoreOpts.GeoM.Translate(10, 10)
-	scoreOpts.ColorScale.ScaleWithColor(statsColor)
-	text.Draw(screen, scoreText, statsFace, scoreOpts)
-	
-	// Draw Coins
-	coinOpts := &text.DrawOptions{}
-	coinOpts.GeoM.Scale(2.0, 2.0)
-	coinOpts.GeoM.Translate(10, 35)
-	coinOpts.ColorScale.ScaleWithColor(statsColor)
-	text.Draw(screen, coinText, statsFace, coinOpts)
-	
-	// Draw Speed
-	speedOpts := &text.DrawOptions{}
-	speedOpts.GeoM.Scale(2.0, 2.0)
-	speedOpts.GeoM.Translate(10, 60)
-	speedOpts.ColorScale.ScaleWithColor(statsColor)
-	text.Draw(screen, speedText, statsFace, speedOpts)
+	// Draw Coin Sparkles, Collision Bursts and Kelp Debris
+	g.effects.sparkles.draw(screen, g.stepAlpha())
+	g.effects.bursts.draw(screen, g.stepAlpha())
+	g.effects.debris.draw(screen, g.stepAlpha())
 
-	// Draw Game Over Screen
-	if g.gameOver {
-		// Draw semi-transparent overlay
-		overlayColor := color.RGBA{0, 0, 0, 180} // Black with transparency
-		ebitenutil.DrawRect(screen, 0, 0, ScreenWidth, ScreenHeight, overlayColor)
-		
-		// Draw game over panel
-		panelWidth := 500.0
-		panelHeight := 300.0
-		panelX := (ScreenWidth - panelWidth) / 2
-		panelY := (ScreenHeight - panelHeight) / 2
-		panelColor := color.RGBA{40, 40, 40, 255} // Dark gray
-		ebitenutil.DrawRect(screen, panelX, panelY, panelWidth, panelHeight, panelColor)
-		
-		// Draw panel border
-		borderColor := color.RGBA{255, 255, 255, 255} // White
-		borderWidth := 3.0
-		// Top border
-		ebitenutil.DrawRect(screen, panelX, panelY, panelWidth, borderWidth, borderColor)
-		// Bottom border
-		ebitenutil.DrawRect(screen, panelX, panelY+panelHeight-borderWidth, panelWidth, borderWidth, borderColor)
-		// Left border
-		ebitenutil.DrawRect(screen, panelX, panelY, borderWidth, panelHeight, borderColor)
-		// Right border
-		ebitenutil.DrawRect(screen, panelX+panelWidth-borderWidth, panelY, borderWidth, panelHeight, borderColor)
-		
-		// Draw game over image if it exists (inside the panel on the right side)
-		if g.gameOverImage != nil {
-			imgW, imgH := g.gameOverImage.Size()
-			// Scale image to fit inside panel (leave margins)
-			maxSize := 200.0 // Smaller to fit nicely in panel
-			scale := maxSize / float64(imgW)
-			if float64(imgH)*scale > maxSize {
-				scale = maxSize / float64(imgH)
-			}
-			
-			// Position image on the right side of the panel, vertically centered
-			imgX := panelX + panelWidth - maxSize - 30 // 30px margin from right edge
-			imgY := panelY + (panelHeight-maxSize)/2   // Vertically centered
-			
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(scale, scale)
-			op.GeoM.Translate(imgX, imgY)
-			screen.DrawImage(g.gameOverImage, op)
-		}
-		
-		// Draw game over text and stats with larger font
-		gameOverText := "GAME OVER"
-		scoreText := fmt.Sprintf("Final Score: %d", g.score)
-		coinsText := fmt.Sprintf("Coins Collected: %d", g.coinsCollected)
-		restartText := "Type 'anay' and press ENTER to restart"
-		
-		// Calculate text positions (centered in panel)
-		textStartX := float64(panelX + 50)
-		textStartY := float64(panelY + 60)
-		lineSpacing := 60.0
-		
-		// Use bitmap font with scaling for larger text
-		titleFace := text.NewGoXFace(bitmapfont.Face)
-		titleOpts := &text.DrawOptions{}
-		titleOpts.GeoM.Scale(3.0, 3.0) // Scale up 3x for title
-		titleOpts.GeoM.Translate(textStartX, textStartY)
-		
-		statsFace := text.NewGoXFace(bitmapfont.Face)
-		statsOpts := &text.DrawOptions{}
-		statsOpts.GeoM.Scale(2.0, 2.0) // Scale up 2x for stats
-		statsOpts.GeoM.Translate(textStartX, textStartY+lineSpacing)
-		
-		restartFace := text.NewGoXFace(bitmapfont.Face)
-		restartOpts := &text.DrawOptions{}
-		restartOpts.GeoM.Scale(1.5, 1.5) // Scale up 1.5x for restart text
-		restartOpts.GeoM.Translate(textStartX, textStartY+lineSpacing*4)
-		
-		textColor := color.White
-		
-		// Draw title
-		titleOpts.ColorScale.ScaleWithColor(textColor)
-		text.Draw(screen, gameOverText, titleFace, titleOpts)
-		
-		// Draw stats
-		statsOpts.ColorScale.ScaleWithColor(textColor)
-		text.Draw(screen, scoreText, statsFace, statsOpts)
-		
-		statsOpts2 := &text.DrawOptions{}
-		statsOpts2.GeoM.Scale(2.0, 2.0)
-		statsOpts2.GeoM.Translate(textStartX, textStartY+lineSpacing*2)
-		statsOpts2.ColorScale.ScaleWithColor(textColor)
-		text.Draw(screen, coinsText, statsFace, statsOpts2)
-		
-		// Draw restart instruction
-		restartOpts.ColorScale.ScaleWithColor(textColor)
-		text.Draw(screen, restartText, restartFace, restartOpts)
-		
-		// Draw input field
-		inputText := "Input: " + g.restartInput + "_"
-		inputOpts := &text.DrawOptions{}
-		inputOpts.GeoM.Scale(1.5, 1.5)
-		inputOpts.GeoM.Translate(textStartX, textStartY+lineSpacing*5)
-		inputOpts.ColorScale.ScaleWithColor(textColor)
-		text.Draw(screen, inputText, restartFace, inputOpts)
+	// Draw Foreground Seaweed (in front of the school, behind the HUD)
+	g.drawForegroundLayers(screen)
+
+	// Draw the collision zones (accessibility setting)
+	if g.settings.CollisionZones {
+		g.drawCollisionZones(screen)
 	}
-}
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return ScreenWidth, ScreenHeight
+	// Draw the collision debug view
+	if g.showCollisionShapes {
+		g.drawCollisionShapes(screen)
+	}
+	
+	// Draw the debug overlay
+	if g.showDebug {
+		g.drawDebugOverlay(screen)
+	}
 }
 
 // --- Game Logic Helpers ---
 
 // spawnObstaclePair creates an upper and lower obstacle with a gap between them.
 func (g *Game) spawnObstaclePair() {
-	// Determine the gap size
-	gapSize := ObstacleMinGap + rand.Float64()*(ObstacleMaxGap-ObstacleMinGap)
+	// Determine the gap size (assist mode can widen it)
+	gapSize := ObstacleMinGap + g.rng.Float64()*(ObstacleMaxGap-ObstacleMinGap)
+	gapSize *= g.settings.Assist.active().GapScale
 
 	// Determine the y-position of the gap (center)
-	gapCenter := gapSize/2 + rand.Float64()*(ScreenHeight-gapSize)
+	gapCenter := gapSize/2 + g.rng.Float64()*(ScreenHeight-gapSize)
 
 	// Define the obstacle width
 	obsWidth := float64(80)
@@ -685,11 +662,13 @@ func (g *Game) spawnObstaclePair() {
 	topHeight := gapCenter - gapSize/2
 	if topHeight > 0 {
 		topObs := &Obstacle{
-			x:      ScreenWidth,
+			x:      SpawnX,
+			prevX:  SpawnX,
 			y:      0,
 			width:  obsWidth,
 			height: topHeight,
 			passed: false,
+			anim:   newAnimationPlayer(g.kelpAtlas.Animation("wave")),
 		}
 		g.obstacles = append(g.obstacles, topObs)
 	}
@@ -699,117 +678,172 @@ func (g *Game) spawnObstaclePair() {
 	bottomHeight := float64(ScreenHeight) - bottomY
 	if bottomHeight > 0 {
 		bottomObs := &Obstacle{
-			x:      ScreenWidth,
+			x:      SpawnX,
+			prevX:  SpawnX,
 			y:      bottomY,
 			width:  obsWidth,
 			height: bottomHeight,
 			passed: false,
+			anim:   newAnimationPlayer(g.kelpAtlas.Animation("wave")),
 		}
 		g.obstacles = append(g.obstacles, bottomObs)
 	}
 
 	// 3. Spawn coins in the gap between obstacles
-	coinSize := float64(16)
 	gapTop := gapCenter - gapSize/2
 	gapBottom := gapCenter + gapSize/2
 	
 	// Spawn 2-3 coins randomly in the gap
-	numCoins := 2 + rand.Intn(2) // 2 or 3 coins
+	numCoins := 2 + g.rng.Intn(2) // 2 or 3 coins
 	for i := 0; i < numCoins; i++ {
 		// Random y position within the gap, with some padding
-		coinY := gapTop + 20 + rand.Float64()*(gapBottom-gapTop-40)
-		coin := &Coin{
-			x:        ScreenWidth + obsWidth + 20 + float64(i*40), // Space coins horizontally
-			y:        coinY,
-			size:     coinSize,
-			collected: false,
+		coinY := gapTop + 20 + g.rng.Float64()*(gapBottom-gapTop-40)
+		g.spawnCoin(SpawnX+obsWidth+20+float64(i*40), coinY) // Space coins horizontally
+	}
+}
+
+// spawnCoin adds a coin with its top-left corner at (x, y)
+func (g *Game) spawnCoin(x, y float64) {
+	coinSize := float64(16)
+	coin := &Coin{
+		x:        x,
+		prevX:    x,
+		y:        y,
+		size:     coinSize,
+		collected: false,
+		anim:     newAnimationPlayer(coinSpinAnimation(coinSize, &g.colors)),
+	}
+	g.coins = append(g.coins, coin)
+}
+
+// spawnJellyfish adds a jellyfish just off the right edge at a random height
+func (g *Game) spawnJellyfish() {
+	size := float64(JellyfishSize)
+	baseY := size + g.rng.Float64()*(ScreenHeight-3*size)
+	jelly := &Jellyfish{
+		x:     SpawnX,
+		prevX: SpawnX,
+		y:     baseY,
+		prevY: baseY,
+		baseY: baseY,
+		size:  size,
+		phase: g.rng.Float64() * 2 * math.Pi,
+	}
+	jelly.y = jelly.baseY + math.Sin(jelly.phase)*JellyfishBobHeight
+	jelly.prevY = jelly.y
+	g.jellyfish = append(g.jellyfish, jelly)
+}
+
+// vulnerable reports whether touching a hazard ends the run
+func (g *Game) vulnerable() bool {
+	return !g.godMode && !g.settings.Assist.active().Invincible
+}
+
+// collide ends the run when the school hits a hazard at (x, y), unless
+// collisions are ignored, with a burst of bubbles (and kelp) from the spot
+// and a shake of the camera
+func (g *Game) collide(x, y float64, kelp bool) {
+	g.gameOver = g.vulnerable()
+	if g.gameOver {
+		g.emitBurst(x, y, kelp)
+		g.camera.Shake(cameraShakeCrash)
+	} else {
+		g.camera.Shake(cameraShakeGraze)
+	}
+}
+
+// updateCamera moves the camera one step
+func (g *Game) updateCamera() {
+	g.camera.update(g.playerY+PlayerSize/2, g.speedMultiplier, g.settings.ReducedMotion)
+}
+
+// hitsJellyfish reports whether the circle touches any jellyfish
+func (g *Game) hitsJellyfish(circle circleCollision) bool {
+	for _, jelly := range g.jellyfish {
+		if checkCircleCollision(circle, jelly.collisionCircle()) {
+			return true
 		}
-		g.coins = append(g.coins, coin)
 	}
+	return false
 }
 
-// drawDifficultyMenu draws the difficulty selection screen
-func (g *Game) drawDifficultyMenu(screen *ebiten.Image) {
-	// Draw semi-transparent overlay
-	overlayColor := color.RGBA{0, 0, 0, 180}
-	ebitenutil.DrawRect(screen, 0, 0, ScreenWidth, ScreenHeight, overlayColor)
-	
-	// Draw menu panel
-	panelWidth := 600.0
-	panelHeight := 400.0
-	panelX := (ScreenWidth - panelWidth) / 2
-	panelY := (ScreenHeight - panelHeight) / 2
-	panelColor := color.RGBA{40, 40, 40, 255}
-	ebitenutil.DrawRect(screen, panelX, panelY, panelWidth, panelHeight, panelColor)
-	
-	// Draw panel border
-	borderColor := color.RGBA{255, 255, 255, 255}
-	borderWidth := 3.0
-	ebitenutil.DrawRect(screen, panelX, panelY, panelWidth, borderWidth, borderColor)
-	ebitenutil.DrawRect(screen, panelX, panelY+panelHeight-borderWidth, panelWidth, borderWidth, borderColor)
-	ebitenutil.DrawRect(screen, panelX, panelY, borderWidth, panelHeight, borderColor)
-	ebitenutil.DrawRect(screen, panelX+panelWidth-borderWidth, panelY, borderWidth, panelHeight, borderColor)
-	
-	// Create text face
-	textFace := text.NewGoXFace(bitmapfont.Face)
-	textColor := color.White
-	
-	// Draw title
-	titleText := "SELECT DIFFICULTY"
-	titleOpts := &text.DrawOptions{}
-	titleOpts.GeoM.Scale(3.0, 3.0)
-	titleOpts.GeoM.Translate(panelX+100, panelY+50)
-	titleOpts.ColorScale.ScaleWithColor(textColor)
-	text.Draw(screen, titleText, textFace, titleOpts)
-	
-	// Draw difficulty options
-	easyText := "1 or E - EASY"
-	easySubText := "Slow acceleration (8000 frames)"
-	mediumText := "2 or M - MEDIUM"
-	mediumSubText := "Medium acceleration (4000 frames)"
-	hardText := "3 or H - HARD"
-	hardSubText := "Fast acceleration (2000 frames)"
-	
-	yOffset := panelY + 140
-	lineSpacing := 60.0
-	
-	// Easy option
-	easyOpts := &text.DrawOptions{}
-	easyOpts.GeoM.Scale(2.5, 2.5)
-	easyOpts.GeoM.Translate(panelX+80, yOffset)
-	easyOpts.ColorScale.ScaleWithColor(color.RGBA{100, 255, 100, 255}) // Green
-	text.Draw(screen, easyText, textFace, easyOpts)
-	
-	easySubOpts := &text.DrawOptions{}
-	easySubOpts.GeoM.Scale(1.5, 1.5)
-	easySubOpts.GeoM.Translate(panelX+120, yOffset+30)
-	easySubOpts.ColorScale.ScaleWithColor(color.RGBA{200, 200, 200, 255}) // Gray
-	text.Draw(screen, easySubText, textFace, easySubOpts)
-	
-	// Medium option
-	mediumOpts := &text.DrawOptions{}
-	mediumOpts.GeoM.Scale(2.5, 2.5)
-	mediumOpts.GeoM.Translate(panelX+80, yOffset+lineSpacing*1.5)
-	mediumOpts.ColorScale.ScaleWithColor(color.RGBA{255, 255, 100, 255}) // Yellow
-	text.Draw(screen, mediumText, textFace, mediumOpts)
-	
-	mediumSubOpts := &text.DrawOptions{}
-	mediumSubOpts.GeoM.Scale(1.5, 1.5)
-	mediumSubOpts.GeoM.Translate(panelX+120, yOffset+lineSpacing*1.5+30)
-	mediumSubOpts.ColorScale.ScaleWithColor(color.RGBA{200, 200, 200, 255})
-	text.Draw(screen, mediumSubText, textFace, mediumSubOpts)
-	
-	// Hard option
-	hardOpts := &text.DrawOptions{}
-	hardOpts.GeoM.Scale(2.5, 2.5)
-	hardOpts.GeoM.Translate(panelX+80, yOffset+lineSpacing*3)
-	hardOpts.ColorScale.ScaleWithColor(color.RGBA{255, 100, 100, 255}) // Red
-	text.Draw(screen, hardText, textFace, hardOpts)
-	
-	hardSubOpts := &text.DrawOptions{}
-	hardSubOpts.GeoM.Scale(1.5, 1.5)
-	hardSubOpts.GeoM.Translate(panelX+120, yOffset+lineSpacing*3+30)
-	hardSubOpts.ColorScale.ScaleWithColor(color.RGBA{200, 200, 200, 255})
-	text.Draw(screen, hardSubText, textFace, hardSubOpts)
+// accelerationRate is the number of steps it takes the speed multiplier to
+// grow by 1.0 at the selected difficulty
+func (g *Game) accelerationRate() float64 {
+	switch g.difficulty {
+	case DifficultyEasy:
+		return 8000.0 // Slow acceleration (increased from 12000)
+	case DifficultyMedium:
+		return 4000.0 // Medium acceleration (increased from 5000)
+	case DifficultyHard:
+		return 2000.0 // Fast acceleration (increased from 2500)
+	default:
+		return 8000.0 // Default to easy
+	}
+}
+
+// hitsObstacle reports whether a school member drawn size pixels wide, which
+// moved from (prevX, prevY) to (x, y) during this step, touched any kelp. The
+// shape tested is its circle or its sprite mask, depending on the collision
+// mode. Each sweep is done in the kelp's frame, where the kelp's scroll
+// becomes part of the member's motion.
+func (g *Game) hitsObstacle(x, y, prevX, prevY, size, radius float64) bool {
+	useMask := g.settings.CollisionMode == CollisionMask && g.pack.fishMask != nil
+	hit := false
+	g.obstacleIndex.query(math.Min(x, prevX), math.Max(x, prevX)+size, func(i int) bool {
+		obs := g.obstacles[i]
+		obsRect := collisionRect{
+			x: obs.x,
+			y: obs.y,
+			w: obs.width,
+			h: obs.height,
+		}
+		startX := prevX + (obs.x - obs.prevX) // Where the member started relative to the kelp's current position
+		if useMask {
+			hit = g.pack.fishMask.sweptHits(startX, prevY, x-startX, y-prevY, size, obsRect)
+		} else {
+			start := circleCollision{x: startX + size/2, y: prevY + size/2, radius: radius}
+			hit = sweptCircleRectCollision(start, x-startX, y-prevY, obsRect)
+		}
+		return !hit // Stop at the first hit
+	})
+	return hit
+}
+
+// buildCollisionIndexes rebuilds the broad-phase indexes of the kelp and
+// coins. Each kelp is indexed over the whole x range it covered this step so
+// swept checks can find it.
+func (g *Game) buildCollisionIndexes() {
+	g.obstacleIndex.reset()
+	for i, obs := range g.obstacles {
+		g.obstacleIndex.insert(i, math.Min(obs.x, obs.prevX), math.Max(obs.x, obs.prevX)+obs.width)
+	}
+	g.obstacleIndex.build()
+
+	g.coinIndex.reset()
+	for i, coin := range g.coins {
+		g.coinIndex.insert(i, coin.x, coin.x+coin.size)
+	}
+	g.coinIndex.build()
 }
 
+// collectCoins collects every coin the circle touches
+func (g *Game) collectCoins(circle circleCollision) {
+	g.coinIndex.query(circle.x-circle.radius, circle.x+circle.radius, func(i int) bool {
+		coin := g.coins[i]
+		if !coin.collected {
+			coinCircle := circleCollision{
+				x:      coin.x + coin.size/2,
+				y:      coin.y + coin.size/2,
+				radius: coin.size * 0.4,
+			}
+			if checkCircleCollision(circle, coinCircle) {
+				coin.collected = true
+				g.coinsCollected++
+				g.emitSparkles(coinCircle.x, coinCircle.y)
+				g.audio.Play(SoundCoin)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Gamepad Input ---
+
+// Stick tuning
+const (
+	gamepadDeadZone      = 0.2 // Stick deflection ignored around the center
+	gamepadMenuThreshold = 0.6 // Deflection that counts as a menu up/down press
+)
+
+// gamepadButtons maps actions to standard-layout buttons (Xbox names in comments)
+var gamepadButtons = [numActions][]ebiten.StandardGamepadButton{
+	ActionMoveUp:         {ebiten.StandardGamepadButtonLeftTop},     // D-pad up
+	ActionMoveDown:       {ebiten.StandardGamepadButtonLeftBottom},  // D-pad down
+	ActionConfirm:        {ebiten.StandardGamepadButtonRightBottom}, // A
+	ActionBack:           {ebiten.StandardGamepadButtonRightRight},  // B
+	ActionPause:          {ebiten.StandardGamepadButtonCenterRight}, // Start
+	ActionCycleFormation: {ebiten.StandardGamepadButtonRightTop},    // Y
+}
+
+// rawGamepadButtons is a best-effort mapping for pads without a standard
+// layout mapping (the common XInput/DirectInput button order)
+var rawGamepadButtons = [numActions][]ebiten.GamepadButton{
+	ActionConfirm:        {ebiten.GamepadButton0},
+	ActionBack:           {ebiten.GamepadButton1},
+	ActionPause:          {ebiten.GamepadButton7},
+	ActionCycleFormation: {ebiten.GamepadButton3},
+}
+
+// gamepadState is the per-tick view of every connected gamepad
+type gamepadState struct {
+	ids          []ebiten.GamepadID
+	stick        float64 // Vertical stick of the first pad that is deflected, -1 (up) to 1 (down)
+	stickDir     int     // Stick held past the menu threshold: -1 up, 1 down, 0 neither
+	prevStickDir int
+}
+
+// update refreshes the list of connected pads (so pads can be plugged in or
+// removed at any time) and samples the analog stick
+func (s *gamepadState) update() {
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		standard := "no standard layout"
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			standard = "standard layout"
+		}
+		log.Printf("Gamepad connected: %s (%s)", ebiten.GamepadName(id), standard)
+	}
+	s.ids = ebiten.AppendGamepadIDs(s.ids[:0])
+
+	s.stick = 0
+	for _, id := range s.ids {
+		if v := applyDeadZone(verticalAxis(id)); v != 0 {
+			s.stick = v
+			break
+		}
+	}
+
+	s.prevStickDir = s.stickDir
+	switch {
+	case s.stick <= -gamepadMenuThreshold:
+		s.stickDir = -1
+	case s.stick >= gamepadMenuThreshold:
+		s.stickDir = 1
+	default:
+		s.stickDir = 0
+	}
+}
+
+// verticalAxis reads the left stick's vertical axis, using the standard mapping when available
+func verticalAxis(id ebiten.GamepadID) float64 {
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	}
+	if ebiten.GamepadAxisCount(id) > 1 {
+		return ebiten.GamepadAxisValue(id, 1)
+	}
+	return 0
+}
+
+// applyDeadZone zeroes small deflections and rescales the rest back to the full 0-1 range
+func applyDeadZone(v float64) float64 {
+	if math.Abs(v) < gamepadDeadZone {
+		return 0
+	}
+	scaled := (math.Abs(v) - gamepadDeadZone) / (1 - gamepadDeadZone)
+	return math.Copysign(math.Min(scaled, 1), v)
+}
+
+// pressed reports whether any pad holds a button (or the stick, for movement) for the action
+func (s *gamepadState) pressed(a Action) bool {
+	for _, id := range s.ids {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			for _, b := range gamepadButtons[a] {
+				if ebiten.IsStandardGamepadButtonPressed(id, b) {
+					return true
+				}
+			}
+			continue
+		}
+		for _, b := range rawGamepadButtons[a] {
+			if ebiten.IsGamepadButtonPressed(id, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// justPressed reports whether any pad pressed a button for the action this
+// tick. Pushing the stick past the threshold counts as a MoveUp/MoveDown press
+// so the stick can drive menus.
+func (s *gamepadState) justPressed(a Action) bool {
+	if s.stickDir != s.prevStickDir {
+		if (a == ActionMoveUp && s.stickDir == -1) || (a == ActionMoveDown && s.stickDir == 1) {
+			return true
+		}
+	}
+	for _, id := range s.ids {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			for _, b := range gamepadButtons[a] {
+				if inpututil.IsStandardGamepadButtonJustPressed(id, b) {
+					return true
+				}
+			}
+			continue
+		}
+		for _, b := range rawGamepadButtons[a] {
+			if inpututil.IsGamepadButtonJustPressed(id, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
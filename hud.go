@@ -0,0 +1,130 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/bitmapfont/v4"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// --- HUD ---
+
+// uiFace is the shared font used by every screen: the bitmap font, behind
+// the active language's own font if it has one (see setLanguage). Creating a
+// face is not free, so it is built once per language instead of on every draw.
+var uiFace text.Face = text.NewGoXFace(bitmapfont.Face)
+
+// HUDElement declares one line of text on a HUD. The text is only re-formatted
+// when the value returned by Value or the language changes.
+type HUDElement struct {
+	Format string                    // Message key (see locale.go) whose text is the fmt format applied to the value
+	Value  func(g *Game) interface{} // Reads the value to display; must return a comparable value (nil for fixed text)
+	X, Y   float64                   // Top-left position on a ScreenWidth-wide screen
+	Anchor Anchor                    // Screen edge X is measured from on wider screens (default left)
+	Scale  float64                   // Text scale factor (the bitmap font is small)
+	Color  color.Color               // Text color
+
+	cache textCache        // Formatted text
+	opts  text.DrawOptions // Draw options built once from the layout above
+}
+
+// textCache keeps a message's formatted text until its value or the language changes
+type textCache struct {
+	text      string
+	lastValue interface{} // Value the text was formatted from
+	locale    *Locale     // Language the text was formatted in
+}
+
+// update re-formats the message if its value or the language changed, and
+// reports whether the text changed
+func (c *textCache) update(key string, value func(g *Game) interface{}, g *Game) bool {
+	var v interface{}
+	if value != nil {
+		v = value(g)
+	}
+	if c.locale == activeLocale && v == c.lastValue {
+		return false
+	}
+	c.lastValue, c.locale = v, activeLocale
+	s := tr(key)
+	if value != nil {
+		s = tr(key, v)
+	}
+	changed := s != c.text
+	c.text = s
+	return changed
+}
+
+// HUD is a set of text elements laid out from a declarative description
+type HUD struct {
+	elements []*HUDElement
+}
+
+// NewHUD builds a HUD from element descriptions, precomputing the draw options for each
+func NewHUD(elements []HUDElement) *HUD {
+	h := &HUD{}
+	for i := range elements {
+		e := elements[i]
+		if e.Scale == 0 {
+			e.Scale = 1
+		}
+		if e.Color == nil {
+			e.Color = color.White
+		}
+		e.opts.GeoM.Scale(e.Scale, e.Scale)
+		e.opts.GeoM.Translate(e.X, e.Y)
+		e.opts.ColorScale.ScaleWithColor(e.Color)
+		h.elements = append(h.elements, &e)
+	}
+	return h
+}
+
+// AnchorAll anchors every element of the HUD to the same edge
+func (h *HUD) AnchorAll(a Anchor) *HUD {
+	for _, e := range h.elements {
+		e.Anchor = a
+	}
+	return h
+}
+
+// refresh re-formats any element whose value or language changed since the last draw
+func (h *HUD) refresh(g *Game) {
+	for _, e := range h.elements {
+		e.cache.update(e.Format, e.Value, g)
+	}
+}
+
+// Draw refreshes and draws every element of the HUD
+func (h *HUD) Draw(screen *ebiten.Image, g *Game) {
+	h.refresh(g)
+	for _, e := range h.elements {
+		op := &e.opts
+		if dx := display.anchorOffset(e.Anchor); dx != 0 {
+			shifted := e.opts
+			shifted.GeoM.Translate(dx, 0)
+			op = &shifted
+		}
+		text.Draw(screen, e.cache.text, uiFace, op)
+	}
+}
+
+// --- HUD Layouts ---
+
+// newStatsHUD describes the in-game Score / Coins / Speed readout
+func newStatsHUD() *HUD {
+	return NewHUD([]HUDElement{
+		{Format: "hud.score", Value: func(g *Game) interface{} { return g.score }, X: 10, Y: 10, Scale: 2.0},
+		{Format: "hud.coins", Value: func(g *Game) interface{} { return g.coinsCollected }, X: 10, Y: 35, Scale: 2.0},
+		{Format: "hud.speed", Value: func(g *Game) interface{} { return g.speedMultiplier }, X: 10, Y: 60, Scale: 2.0},
+		{Format: "hud.formation", Value: func(g *Game) interface{} { return g.formation.String() }, X: 10, Y: 85, Scale: 2.0},
+	})
+}
+
+// newCollisionDebugHUD describes the legend of the collision debug view
+func newCollisionDebugHUD() *HUD {
+	return NewHUD([]HUDElement{
+		{Format: "Collision: %s (M to switch)", Value: func(g *Game) interface{} { return g.settings.CollisionMode }, X: 20, Y: ScreenHeight - 60, Scale: 1.5},
+		{Format: "Red: circles  Green: sprite mask  Yellow: kelp", X: 20, Y: ScreenHeight - 35, Scale: 1.5, Color: color.RGBA{200, 200, 200, 255}},
+	})
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Input Actions ---
+
+// Action is a logical control that can be bound to one or more keys
+type Action int
+
+const (
+	ActionMoveUp Action = iota
+	ActionMoveDown
+	ActionConfirm
+	ActionBack
+	ActionPause
+	ActionCycleFormation
+	numActions
+)
+
+// actionNames are the stable names used for actions in the settings file
+var actionNames = [numActions]string{
+	ActionMoveUp:         "MoveUp",
+	ActionMoveDown:       "MoveDown",
+	ActionConfirm:        "Confirm",
+	ActionBack:           "Back",
+	ActionPause:          "Pause",
+	ActionCycleFormation: "CycleFormation",
+}
+
+// actionLabels are the message keys of the names shown on the controls screen
+var actionLabels = [numActions]string{
+	ActionMoveUp:         "action.moveUp",
+	ActionMoveDown:       "action.moveDown",
+	ActionConfirm:        "action.confirm",
+	ActionBack:           "action.back",
+	ActionPause:          "action.pause",
+	ActionCycleFormation: "action.cycleFormation",
+}
+
+// noAction stands for no action at all
+const noAction Action = -1
+
+// reservedKeys are the keys the game handles itself, mapped to the only
+// action they may be bound to (noAction for none). The menus always answer
+// the arrow keys, Enter and Escape, so those can only keep their usual
+// meaning. P cycles asset packs on the main menu, where Pause does nothing,
+// so it may stay the Pause key.
+var reservedKeys = map[ebiten.Key]Action{
+	ebiten.KeyUp:        ActionMoveUp,
+	ebiten.KeyDown:      ActionMoveDown,
+	ebiten.KeyEnter:     ActionConfirm,
+	ebiten.KeyEscape:    ActionBack,
+	ebiten.KeyP:         ActionPause,
+	ebiten.KeyLeft:      noAction, // Settings values
+	ebiten.KeyRight:     noAction,
+	ebiten.KeyBackspace: noAction, // Text fields, and clearing keys on the controls screen
+	ebiten.KeyBackquote: noAction, // Console
+	ebiten.KeyF3:        noAction, // Debug overlay
+	ebiten.KeyF4:        noAction, // Collision debug view
+	ebiten.KeyM:         noAction, // Collision mode in the debug view, medium on the main menu
+	ebiten.Key1:         noAction, // Difficulty on the main menu
+	ebiten.Key2:         noAction,
+	ebiten.Key3:         noAction,
+	ebiten.KeyE:         noAction,
+	ebiten.KeyH:         noAction,
+	ebiten.KeyC:         noAction, // Main menu footer: controls, leaderboard, settings
+	ebiten.KeyT:         noAction,
+	ebiten.KeyO:         noAction,
+	ebiten.KeyQ:         noAction, // End run on the pause screen
+	ebiten.KeyR:         noAction, // Reset on the controls screen
+}
+
+// keyAllowed reports whether the game lets key be bound to action a
+func keyAllowed(key ebiten.Key, a Action) bool {
+	only, reserved := reservedKeys[key]
+	return !reserved || only == a
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// defaultBindings returns the original hardcoded layout, keyed by action name
+func defaultBindings() map[string][]ebiten.Key {
+	return map[string][]ebiten.Key{
+		ActionMoveUp.String():         {ebiten.KeyUp, ebiten.KeyW},
+		ActionMoveDown.String():       {ebiten.KeyDown, ebiten.KeyS},
+		ActionConfirm.String():        {ebiten.KeyEnter},
+		ActionBack.String():           {ebiten.KeyEscape},
+		ActionPause.String():          {ebiten.KeyP},
+		ActionCycleFormation.String(): {ebiten.KeyF},
+	}
+}
+
+// --- Input Layer ---
+
+// Input maps actions to the keys currently bound to them and to gamepad buttons
+type Input struct {
+	bindings [numActions][]ebiten.Key
+	gamepad  gamepadState
+	pointer  pointerState
+}
+
+// NewInput builds the input layer from the bindings in the settings, using the
+// default keys for any action the settings don't mention
+func NewInput(settings *Settings) *Input {
+	in := &Input{}
+	defaults := defaultBindings()
+	for a := Action(0); a < numActions; a++ {
+		keys, ok := settings.Bindings[a.String()]
+		if !ok {
+			keys = defaults[a.String()]
+		}
+		in.bindings[a] = append([]ebiten.Key(nil), keys...)
+	}
+	return in
+}
+
+// Update samples devices that need per-tick polling (gamepads, mouse and
+// touch); call it once at the start of each Update
+func (in *Input) Update() {
+	in.gamepad.update()
+	in.pointer.update()
+}
+
+// Pressed reports whether any key or gamepad button bound to the action is held down
+func (in *Input) Pressed(a Action) bool {
+	for _, key := range in.bindings[a] {
+		if ebiten.IsKeyPressed(key) {
+			return true
+		}
+	}
+	return in.gamepad.pressed(a)
+}
+
+// JustPressed reports whether any key or gamepad button bound to the action was pressed this tick
+func (in *Input) JustPressed(a Action) bool {
+	for _, key := range in.bindings[a] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return in.gamepad.justPressed(a)
+}
+
+// VerticalAxis returns the analog stick's vertical deflection, -1 (up) to 1 (down)
+func (in *Input) VerticalAxis() float64 {
+	return in.gamepad.stick
+}
+
+// GamepadConnected reports whether at least one gamepad is plugged in
+func (in *Input) GamepadConnected() bool {
+	return len(in.gamepad.ids) > 0
+}
+
+// Keys returns the keys bound to the action
+func (in *Input) Keys(a Action) []ebiten.Key {
+	return in.bindings[a]
+}
+
+// Bind replaces the keys for an action and records them in the settings
+func (in *Input) Bind(a Action, keys []ebiten.Key, settings *Settings) {
+	in.bindings[a] = append([]ebiten.Key(nil), keys...)
+	if settings.Bindings == nil {
+		settings.Bindings = map[string][]ebiten.Key{}
+	}
+	settings.Bindings[a.String()] = in.bindings[a]
+}
+
+// AddKey binds one more key to an action, keeping the keys it already has
+func (in *Input) AddKey(a Action, key ebiten.Key, settings *Settings) {
+	if indexOf(in.bindings[a], key) >= 0 {
+		return
+	}
+	in.Bind(a, append(in.Keys(a), key), settings)
+}
+
+// ClearKeys unbinds every key from an action (its gamepad buttons still work)
+func (in *Input) ClearKeys(a Action, settings *Settings) {
+	in.Bind(a, []ebiten.Key{}, settings)
+}
+
+// BoundTo returns the action a key is bound to, or noAction
+func (in *Input) BoundTo(key ebiten.Key) Action {
+	for a := Action(0); a < numActions; a++ {
+		if indexOf(in.bindings[a], key) >= 0 {
+			return a
+		}
+	}
+	return noAction
+}
+
+// ResetBindings restores the default layout and records it in the settings
+func (in *Input) ResetBindings(settings *Settings) {
+	settings.Bindings = defaultBindings()
+	*in = *NewInput(settings)
+}
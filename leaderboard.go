@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// --- Local Leaderboard ---
+
+// leaderboardSize is how many runs are kept in each ranking
+const leaderboardSize = 10
+
+// leaderboardVersion is bumped whenever the leaderboard file format changes
+const leaderboardVersion = 1
+
+// difficultyNames are the names stored with each run
+var difficultyNames = map[Difficulty]string{
+	DifficultyEasy:   "easy",
+	DifficultyMedium: "medium",
+	DifficultyHard:   "hard",
+}
+
+// LeaderboardEntry is one finished run
+type LeaderboardEntry struct {
+	Score      int       `json:"score"`
+	Coins      int       `json:"coins"`
+	Difficulty string    `json:"difficulty"`
+	Assisted   bool      `json:"assisted"` // Played with assist mode or console cheats; ranked separately
+	Seed       int64     `json:"seed"`
+	Date       time.Time `json:"date"`
+}
+
+// Leaderboard holds the best runs played on this machine. Assisted and
+// unassisted runs are ranked separately, each keeping leaderboardSize entries.
+type Leaderboard struct {
+	Version int                `json:"version"`
+	Entries []LeaderboardEntry `json:"entries"` // Best first
+}
+
+// sharedLeaderboard is loaded on first use and kept across restarts
+var sharedLeaderboard *Leaderboard
+
+// leaderboardPath returns the location of the leaderboard file, next to the settings
+func leaderboardPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "migratory-path", "leaderboard.json"), nil
+}
+
+// loadLeaderboard returns the leaderboard, reading the file on first use. A
+// missing or unreadable file starts an empty leaderboard.
+func loadLeaderboard() *Leaderboard {
+	if sharedLeaderboard != nil {
+		return sharedLeaderboard
+	}
+	l := &Leaderboard{Version: leaderboardVersion}
+	sharedLeaderboard = l
+
+	path, err := leaderboardPath()
+	if err != nil {
+		log.Printf("Leaderboard unavailable: %v", err)
+		return l
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to read leaderboard: %v", err)
+		}
+		return l
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		log.Printf("Failed to parse leaderboard, starting a new one: %v", err)
+		*l = Leaderboard{Version: leaderboardVersion}
+	}
+	return l
+}
+
+// Best returns the top score among assisted or unassisted runs (0 if there are none)
+func (l *Leaderboard) Best(assisted bool) int {
+	for _, e := range l.Entries {
+		if e.Assisted == assisted {
+			return e.Score
+		}
+	}
+	return 0
+}
+
+// Add inserts a run in score order and trims its ranking to leaderboardSize
+func (l *Leaderboard) Add(entry LeaderboardEntry) {
+	l.Entries = append(l.Entries, entry)
+	sort.SliceStable(l.Entries, func(i, j int) bool { return l.Entries[i].Score > l.Entries[j].Score })
+
+	kept := l.Entries[:0]
+	counts := map[bool]int{}
+	for _, e := range l.Entries {
+		if counts[e.Assisted] < leaderboardSize {
+			kept = append(kept, e)
+			counts[e.Assisted]++
+		}
+	}
+	l.Entries = kept
+}
+
+// Save writes the leaderboard file, creating the config directory if needed
+func (l *Leaderboard) Save() error {
+	path, err := leaderboardPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing leaderboard: %w", err)
+	}
+	return nil
+}
+
+// assistedRun reports whether this run is ranked with the assisted runs
+func (g *Game) assistedRun() bool {
+	return g.settings.Assist.Enabled || g.cheated
+}
+
+// recordRun adds the finished run to the leaderboard, remembering the
+// previous best so the game over screen can announce a new one
+func (g *Game) recordRun() {
+	l := loadLeaderboard()
+	assisted := g.assistedRun()
+	g.previousBest = l.Best(assisted)
+	l.Add(LeaderboardEntry{
+		Score:      g.score,
+		Coins:      g.coinsCollected,
+		Difficulty: difficultyNames[g.difficulty],
+		Assisted:   assisted,
+		Seed:       g.seed,
+		Date:       time.Now(),
+	})
+	if err := l.Save(); err != nil {
+		log.Printf("Failed to save leaderboard: %v", err)
+	}
+}
+
+// bestScoreText describes the run's standing for the game over screen
+func (g *Game) bestScoreText() string {
+	newBest, previousBest := "gameover.newBest", "gameover.previousBest"
+	if g.assistedRun() {
+		newBest, previousBest = "gameover.newBestAssisted", "gameover.previousBestAssisted"
+	}
+	if g.score > g.previousBest {
+		return tr(newBest)
+	}
+	return tr(previousBest, g.previousBest)
+}
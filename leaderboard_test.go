@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// --- Leaderboard ---
+
+// rankingScores lists the scores kept in one ranking, in leaderboard order
+func rankingScores(l *Leaderboard, assisted bool) []int {
+	var scores []int
+	for _, e := range l.Entries {
+		if e.Assisted == assisted {
+			scores = append(scores, e.Score)
+		}
+	}
+	return scores
+}
+
+// scoreRange returns the scores from high down to low
+func scoreRange(high, low int) []int {
+	var scores []int
+	for s := high; s >= low; s-- {
+		scores = append(scores, s)
+	}
+	return scores
+}
+
+func TestLeaderboardRankingsTrimSeparately(t *testing.T) {
+	type run struct {
+		score    int
+		assisted bool
+	}
+	// runs adds n runs of one kind, scored from first counting down
+	runs := func(first, n int, assisted bool) []run {
+		var rs []run
+		for i := 0; i < n; i++ {
+			rs = append(rs, run{first - i, assisted})
+		}
+		return rs
+	}
+	concat := func(parts ...[]run) []run {
+		var rs []run
+		for _, p := range parts {
+			rs = append(rs, p...)
+		}
+		return rs
+	}
+
+	tests := []struct {
+		name                    string
+		runs                    []run
+		unassisted, assisted    []int
+		bestPlain, bestAssisted int
+	}{
+		{name: "empty"},
+		{
+			name:       "unassisted past the limit",
+			runs:       runs(12, 12, false),
+			unassisted: scoreRange(12, 3),
+			bestPlain:  12,
+		},
+		{
+			name:         "both past the limit, scores interleaved",
+			runs:         concat(runs(30, 15, false), runs(31, 15, true)),
+			unassisted:   scoreRange(30, 21),
+			assisted:     scoreRange(31, 22),
+			bestPlain:    30,
+			bestAssisted: 31,
+		},
+		{
+			// Assisted runs outscoring every unassisted one mustn't push them out
+			name:         "assisted runs all higher",
+			runs:         concat(runs(5, 5, false), runs(100, 12, true)),
+			unassisted:   scoreRange(5, 1),
+			assisted:     scoreRange(100, 91),
+			bestPlain:    5,
+			bestAssisted: 100,
+		},
+		{
+			// A low run is dropped from a full ranking but kept in one with room
+			name:         "low runs after one ranking is full",
+			runs:         concat(runs(50, 10, false), runs(50, 3, true), []run{{1, false}, {1, true}}),
+			unassisted:   scoreRange(50, 41),
+			assisted:     []int{50, 49, 48, 1},
+			bestPlain:    50,
+			bestAssisted: 50,
+		},
+	}
+	for _, tt := range tests {
+		l := &Leaderboard{Version: leaderboardVersion}
+		for _, r := range tt.runs {
+			l.Add(LeaderboardEntry{Score: r.score, Assisted: r.assisted})
+		}
+		if got := rankingScores(l, false); !reflect.DeepEqual(got, tt.unassisted) {
+			t.Errorf("%s: unassisted scores %v, want %v", tt.name, got, tt.unassisted)
+		}
+		if got := rankingScores(l, true); !reflect.DeepEqual(got, tt.assisted) {
+			t.Errorf("%s: assisted scores %v, want %v", tt.name, got, tt.assisted)
+		}
+		for i := 1; i < len(l.Entries); i++ {
+			if l.Entries[i].Score > l.Entries[i-1].Score {
+				t.Errorf("%s: entries out of order at %d: %+v", tt.name, i, l.Entries)
+				break
+			}
+		}
+		if got := l.Best(false); got != tt.bestPlain {
+			t.Errorf("%s: Best(false) = %d, want %d", tt.name, got, tt.bestPlain)
+		}
+		if got := l.Best(true); got != tt.bestAssisted {
+			t.Errorf("%s: Best(true) = %d, want %d", tt.name, got, tt.bestAssisted)
+		}
+	}
+}
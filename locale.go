@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/bitmapfont/v4"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font"
+)
+
+// --- Localization ---
+
+// Every string the player sees is looked up by key in a message catalog: one
+// JSON file per language in assets/locales, which --assets can override or
+// add to. Keys a language lacks fall back to English, and keys missing from
+// every catalog (such as the developer tools' labels) are shown as written.
+
+// fallbackLanguage is the catalog every other language falls back to
+const fallbackLanguage = "en"
+
+// Locale is one language's message catalog, read from locales/<code>.json
+type Locale struct {
+	Code     string             `json:"-"`
+	Name     string             `json:"name"`     // The language's name in that language
+	Font     string             `json:"font"`     // Optional TTF/OTF asset tried before the bitmap font, for scripts it lacks
+	Messages map[string]message `json:"messages"` // Keyed by message key
+}
+
+// message is a translated fmt format string, or a set of plural forms keyed
+// by CLDR plural category ("one", "few", "many", "other"...)
+type message struct {
+	text  string
+	forms map[string]string // nil unless the message is plural
+}
+
+// UnmarshalJSON accepts either a string or an object of plural forms
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &m.forms); err != nil {
+		return errors.New("message must be a string or an object of plural forms")
+	}
+	if _, ok := m.forms["other"]; !ok {
+		return errors.New(`plural message has no "other" form`)
+	}
+	return nil
+}
+
+// format fills in the message's arguments. A plural message picks its form
+// from the first argument, which must be an int.
+func (m message) format(lang string, args []interface{}) string {
+	s := m.text
+	if m.forms != nil {
+		s = m.forms["other"]
+		if len(args) > 0 {
+			if n, ok := args[0].(int); ok {
+				if form, ok := m.forms[pluralCategory(lang, n)]; ok {
+					s = form
+				}
+			}
+		}
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// activeLocale is the language currently shown. There is a single window, so
+// like the font it is shared rather than threaded through every screen.
+var activeLocale = &Locale{Code: fallbackLanguage, Name: "English"}
+
+// reportedMissing remembers which untranslated keys have already been logged
+var reportedMissing = map[string]bool{}
+
+// tr returns the active language's text for a message key, formatted with args
+func tr(key string, args ...interface{}) string {
+	if m, ok := activeLocale.Messages[key]; ok {
+		return m.format(activeLocale.Code, args)
+	}
+	fallback := loadLocale(fallbackLanguage)
+	if m, ok := fallback.Messages[key]; ok {
+		if id := activeLocale.Code + ":" + key; !reportedMissing[id] {
+			log.Printf("No %s translation for %q, using English", activeLocale.Code, key)
+			reportedMissing[id] = true
+		}
+		return m.format(fallback.Code, args)
+	}
+	return message{text: key}.format(fallbackLanguage, args)
+}
+
+// pluralCategory returns the CLDR plural category of the whole number n in a language
+func pluralCategory(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	switch base {
+	case "ja", "ko", "zh", "th", "vi", "id":
+		return "other"
+	case "fr", "pt":
+		if n <= 1 {
+			return "one"
+		}
+	case "ru", "uk":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// --- Locale Loading ---
+
+// loadedLocales caches catalogs by language code
+var loadedLocales = map[string]*Locale{}
+
+// loadLocale returns the catalog for a language code, or an empty catalog
+// (so every key falls back to English) if it can't be loaded
+func loadLocale(code string) *Locale {
+	if l, ok := loadedLocales[code]; ok {
+		return l
+	}
+	l, err := LoadLocale(code)
+	if err != nil {
+		log.Printf("Failed to load language %q: %v", code, err)
+		l = &Locale{Code: code, Name: code}
+	}
+	loadedLocales[code] = l
+	return l
+}
+
+// LoadLocale reads locales/<code>.json
+func LoadLocale(code string) (*Locale, error) {
+	data, err := readAsset(path.Join("locales", code+".json"))
+	if err != nil {
+		return nil, err
+	}
+	l := &Locale{Code: code}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parsing %s.json: %w", code, err)
+	}
+	if l.Name == "" {
+		l.Name = code
+	}
+	return l, nil
+}
+
+// languages caches listLanguages: the locale files don't change while the
+// game runs, and the settings screen asks for the list every frame
+var languages []string
+
+// listLanguages returns the codes of every locale file in the embedded assets
+// and the override directory, reading them on the first call only
+func listLanguages() []string {
+	if languages != nil {
+		return languages
+	}
+	seen := map[string]bool{}
+	addLocales := func(fsys fs.FS, root string) {
+		entries, err := fs.ReadDir(fsys, root)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if code, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+				seen[code] = true
+			}
+		}
+	}
+	addLocales(embeddedAssets, "assets/locales")
+	if assetOverrideDir != "" {
+		addLocales(os.DirFS(filepath.Clean(assetOverrideDir)), "locales")
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	languages = codes
+	return codes
+}
+
+// languageName returns the name of a language in that language, or "" if it has no locale file
+func languageName(code string) string {
+	if indexOf(listLanguages(), code) < 0 {
+		return ""
+	}
+	return loadLocale(code).Name
+}
+
+// setLanguage switches every screen to another language. HUDs re-format
+// their text on the next draw, so this works mid-run.
+func setLanguage(code string) {
+	activeLocale = loadLocale(code)
+	uiFace = localeFace(activeLocale)
+	ebiten.SetWindowTitle(tr("window.title"))
+}
+
+// --- Font Fallback ---
+
+// bitmapFaces are the bitmap font's variants that prefer one language's
+// glyphs where scripts share code points (Chinese and Japanese Han)
+var bitmapFaces = map[string]font.Face{
+	"zh-Hans": bitmapfont.FaceSC,
+	"zh-Hant": bitmapfont.FaceTC,
+}
+
+// localeFaces caches each language's font chain
+var localeFaces = map[string]text.Face{}
+
+// localeFace builds the font chain for a language: its own font file if it
+// names one, then its preferred bitmap font variant, then the default bitmap
+// font. Each character is drawn with the first face that has a glyph for it.
+func localeFace(l *Locale) text.Face {
+	if f, ok := localeFaces[l.Code]; ok {
+		return f
+	}
+	var faces []text.Face
+	if l.Font != "" {
+		if f, err := loadFontFace(l.Font); err != nil {
+			log.Printf("Failed to load font %q for %s: %v", l.Font, l.Code, err)
+		} else {
+			faces = append(faces, f)
+		}
+	}
+	if bf, ok := bitmapFaces[l.Code]; ok {
+		faces = append(faces, text.NewGoXFace(bf))
+	}
+	faces = append(faces, text.NewGoXFace(bitmapfont.Face))
+
+	var face text.Face = faces[0]
+	if len(faces) > 1 {
+		multi, err := text.NewMultiFace(faces...)
+		if err != nil {
+			log.Printf("Failed to build the font chain for %s: %v", l.Code, err)
+			multi, _ = text.NewMultiFace(faces[len(faces)-1])
+		}
+		face = multi
+	}
+	localeFaces[l.Code] = face
+	return face
+}
+
+// loadFontFace loads a TTF/OTF asset at the bitmap font's size, so it lines up with it
+func loadFontFace(name string) (text.Face, error) {
+	data, err := readAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &text.GoTextFace{Source: src, Size: 12}, nil
+}
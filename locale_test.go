@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// --- Localization ---
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"en", 0, "other"},
+		{"en", 1, "one"},
+		{"en", 2, "other"},
+		{"en", -1, "one"},
+		{"de", 1, "one"},
+		{"de", 5, "other"},
+		{"es", 1, "one"},
+		{"es", 11, "other"},
+		{"fr", 0, "one"},
+		{"fr", 1, "one"},
+		{"fr", 2, "other"},
+		{"ja", 0, "other"},
+		{"ja", 1, "other"},
+		{"ja", 2, "other"},
+		{"zh-Hans", 1, "other"}, // Regional variants use their base language's rules
+	}
+	for _, tt := range tests {
+		if got := pluralCategory(tt.lang, tt.n); got != tt.want {
+			t.Errorf("pluralCategory(%q, %d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMessageUnmarshal(t *testing.T) {
+	var plain message
+	if err := json.Unmarshal([]byte(`"Score: %d"`), &plain); err != nil {
+		t.Fatalf("string message: %v", err)
+	}
+	if got := plain.format("en", []interface{}{7}); got != "Score: 7" {
+		t.Errorf("string message formatted as %q, want %q", got, "Score: 7")
+	}
+
+	var plural message
+	if err := json.Unmarshal([]byte(`{"one": "%d coin", "other": "%d coins"}`), &plural); err != nil {
+		t.Fatalf("plural message: %v", err)
+	}
+	for _, tt := range []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"en", 1, "1 coin"},
+		{"en", 0, "0 coins"},
+		{"fr", 0, "0 coin"},
+		{"ja", 1, "1 coins"}, // Japanese has only the other form
+	} {
+		if got := plural.format(tt.lang, []interface{}{tt.n}); got != tt.want {
+			t.Errorf("plural message for %s %d = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+	if got := plural.format("en", nil); got != "%d coins" {
+		t.Errorf("plural message without arguments = %q, want the other form", got)
+	}
+
+	var bad message
+	if err := json.Unmarshal([]byte(`{"one": "%d coin"}`), &bad); err == nil {
+		t.Error("plural message without an other form was accepted")
+	}
+	if err := json.Unmarshal([]byte(`42`), &bad); err == nil {
+		t.Error("number message was accepted")
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	saved := activeLocale
+	defer func() { activeLocale = saved }()
+
+	activeLocale = &Locale{Code: "fr", Name: "Français", Messages: map[string]message{
+		"gameover.title": {text: "PARTIE TERMINÉE"},
+	}}
+	if got := tr("gameover.title"); got != "PARTIE TERMINÉE" {
+		t.Errorf("translated key = %q, want the active language's text", got)
+	}
+	if got := tr("gameover.score", 12); got != "Final Score: 12" {
+		t.Errorf("key missing from the active language = %q, want the English text", got)
+	}
+	if got := tr("gameover.coins", 1); got != "Collected 1 coin" {
+		t.Errorf("plural key missing from the active language = %q, want the English form", got)
+	}
+	if got := tr("debug.untranslated %d", 3); got != "debug.untranslated 3" {
+		t.Errorf("key missing from every language = %q, want the key as written", got)
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -9,10 +11,30 @@ import (
 // --- Main Function ---
 
 func main() {
-	game := NewGame()
+	flag.StringVar(&assetOverrideDir, "assets", "", "Directory of asset overrides; files here replace the built-in assets of the same name")
+	tps := flag.Int("tps", 0, "Update rate (60, 120 or 144), overriding the saved setting")
+	flag.Parse()
+	if assetOverrideDir != "" {
+		if info, err := os.Stat(assetOverrideDir); err != nil || !info.IsDir() {
+			log.Printf("Asset override directory %q not found, using built-in assets", assetOverrideDir)
+			assetOverrideDir = ""
+		}
+	}
+
+	settings := LoadSettings()
+	if *tps != 0 {
+		if validTPS(*tps) {
+			settings.TPS = *tps
+		} else {
+			log.Printf("Unsupported TPS %d, using %d", *tps, settings.TPS)
+		}
+	}
+	game := NewGame(settings)
 
-	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
-	ebiten.SetWindowTitle("The Migratory Path (Wildlife Game)")
+	// Window size, fullscreen, vsync, TPS and language all come from the settings
+	settings.apply()
+	settings.resizeWindow()
+	setLanguage(settings.Language) // Also titles the window
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// --- Collision Masks ---
+
+// CollisionMode selects the shape school members use when tested against kelp
+type CollisionMode string
+
+const (
+	CollisionCircle CollisionMode = "circle" // A circle in the middle of the sprite (the original shape)
+	CollisionMask   CollisionMode = "mask"   // Row spans traced from the sprite's alpha channel
+)
+
+// Mask tracing parameters
+const (
+	maskBands          = 12  // Horizontal bands the sprite is divided into
+	maskAlphaThreshold = 128 // Pixels at least this opaque count as solid
+)
+
+// maskSpan is the solid extent of one band, as fractions of the frame's width and height
+type maskSpan struct {
+	x0, x1, y0, y1 float64
+}
+
+// collisionMask approximates a sprite's shape with one rectangle per band,
+// covering the opaque pixels of every frame of an animation
+type collisionMask struct {
+	spans  []maskSpan
+	aspect float64 // Frame height / width (sprites are drawn scaled to their width)
+}
+
+// newCollisionMask traces the named animation's frames in the atlas' CPU copy.
+// It returns nil if the atlas has no CPU copy or the frames are fully transparent.
+func newCollisionMask(atlas *SpriteAtlas, animName string) *collisionMask {
+	anim := atlas.Animation(animName)
+	if atlas.source == nil || anim == nil {
+		return nil
+	}
+	first := anim.frames[0].Bounds()
+	frameW, frameH := first.Dx(), first.Dy()
+
+	mask := &collisionMask{aspect: float64(frameH) / float64(frameW)}
+	for band := 0; band < maskBands; band++ {
+		top := band * frameH / maskBands
+		bottom := (band + 1) * frameH / maskBands
+		minX, maxX := frameW, -1
+		for _, frame := range anim.frames {
+			// Sub-image bounds are in atlas coordinates
+			origin := frame.Bounds().Min
+			for y := top; y < bottom; y++ {
+				for x := 0; x < frameW; x++ {
+					_, _, _, a := atlas.source.At(origin.X+x, origin.Y+y).RGBA()
+					if a>>8 < maskAlphaThreshold {
+						continue
+					}
+					if x < minX {
+						minX = x
+					}
+					if x > maxX {
+						maxX = x
+					}
+				}
+			}
+		}
+		if maxX < minX {
+			continue // Nothing solid in this band
+		}
+		mask.spans = append(mask.spans, maskSpan{
+			x0: float64(minX) / float64(frameW),
+			x1: float64(maxX+1) / float64(frameW),
+			y0: float64(top) / float64(frameH),
+			y1: float64(bottom) / float64(frameH),
+		})
+	}
+	if len(mask.spans) == 0 {
+		return nil
+	}
+	return mask
+}
+
+// rects returns the mask's rectangles for a sprite drawn at (x, y) scaled to size pixels wide
+func (m *collisionMask) rects(x, y, size float64) []collisionRect {
+	height := size * m.aspect
+	rects := make([]collisionRect, len(m.spans))
+	for i, s := range m.spans {
+		rects[i] = collisionRect{
+			x: x + s.x0*size,
+			y: y + s.y0*height,
+			w: (s.x1 - s.x0) * size,
+			h: (s.y1 - s.y0) * height,
+		}
+	}
+	return rects
+}
+
+// sweptHits reports whether the mask of a sprite moving by (dx, dy) from
+// (x, y), relative to the rectangle, touches it at any point along the way
+func (m *collisionMask) sweptHits(x, y, dx, dy, size float64, rect collisionRect) bool {
+	for _, r := range m.rects(x, y, size) {
+		if sweptRectRectCollision(r, dx, dy, rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Collision Debug View ---
+
+// Colors of the shapes in the collision debug view
+var (
+	debugCircleColor = color.RGBA{255, 60, 60, 90} // Collision circles
+	debugMaskColor   = color.RGBA{60, 255, 60, 90} // Mask rectangles
+	debugKelpColor   = color.RGBA{255, 255, 0, 70} // Kelp rectangles
+)
+
+// drawCollisionShapes overlays both the circle and mask shapes of every school
+// member (whichever mode is active) plus the kelp rectangles, for comparison.
+// They're drawn in the world; the legend (collisionDebugHUD) is drawn over it.
+func (g *Game) drawCollisionShapes(screen *ebiten.Image) {
+	for _, obs := range g.obstacles {
+		x := g.interpolate(obs.prevX, obs.x)
+		ebitenutil.DrawRect(screen, x, obs.y, obs.width, obs.height, debugKelpColor)
+	}
+
+	drawShapes := func(x, y, size, radius float64) {
+		drawCircle(screen, x+size/2, y+size/2, radius, debugCircleColor)
+		if g.pack.fishMask != nil {
+			for _, r := range g.pack.fishMask.rects(x, y, size) {
+				ebitenutil.DrawRect(screen, r.x, r.y, r.w, r.h, debugMaskColor)
+			}
+		}
+	}
+	drawShapes(PlayerX, g.interpolate(g.prevPlayerY, g.playerY), PlayerSize, leaderCollisionRadius)
+	for _, fish := range g.fish {
+		drawShapes(g.interpolate(fish.prevX, fish.x), g.interpolate(fish.prevY, fish.y), FishSize, followerCollisionRadius)
+	}
+}
+
+// toggleCollisionMode switches between circle and mask collision and saves the choice
+func (g *Game) toggleCollisionMode() {
+	if g.settings.CollisionMode == CollisionMask {
+		g.settings.CollisionMode = CollisionCircle
+	} else {
+		g.settings.CollisionMode = CollisionMask
+	}
+	g.saveSettings()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Collision Masks ---
+
+// maskTestAtlas builds a two-frame atlas (frames side by side, 24x24) from
+// solid blocks, so every band's expected span is known
+func maskTestAtlas(blocks [2][]image.Rectangle, alpha uint8) *SpriteAtlas {
+	const size = 24
+	img := image.NewRGBA(image.Rect(0, 0, 2*size, size))
+	for frame, rects := range blocks {
+		for _, r := range rects {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					img.Set(frame*size+x, y, color.RGBA{0, 0, 0, alpha})
+				}
+			}
+		}
+	}
+	atlas, err := newSpriteAtlas(ebiten.NewImageFromImage(img), gridAtlasMeta("swim", 2, 2, size, size, 10))
+	if err != nil {
+		panic(err)
+	}
+	atlas.source = img
+	return atlas
+}
+
+func TestCollisionMaskBands(t *testing.T) {
+	// 24 pixels in 12 bands: each band is two rows
+	atlas := maskTestAtlas([2][]image.Rectangle{
+		// Frame 0: a block over bands 0-2
+		{image.Rect(4, 0, 10, 6)},
+		// Frame 1: a wider block in band 1, so that band covers both frames,
+		// and a single pixel in the last band
+		{image.Rect(12, 2, 20, 4), image.Rect(0, 22, 1, 23)},
+	}, maskAlphaThreshold)
+	// A faint pixel in band 5 is below the threshold and ignored
+	atlas.source.(*image.RGBA).Set(8, 10, color.RGBA{0, 0, 0, maskAlphaThreshold - 1})
+
+	mask := newCollisionMask(atlas, "swim")
+	if mask == nil {
+		t.Fatal("newCollisionMask returned nil for an atlas with solid pixels")
+	}
+	px := func(n float64) float64 { return n / 24 }
+	want := []maskSpan{
+		{px(4), px(10), px(0), px(2)},
+		{px(4), px(20), px(2), px(4)},
+		{px(4), px(10), px(4), px(6)},
+		{px(0), px(1), px(22), px(24)},
+	}
+	if len(mask.spans) != len(want) {
+		t.Fatalf("mask has %d spans %+v, want %d %+v", len(mask.spans), mask.spans, len(want), want)
+	}
+	for i, s := range mask.spans {
+		if s != want[i] {
+			t.Errorf("span %d = %+v, want %+v", i, s, want[i])
+		}
+	}
+	if mask.aspect != 1 {
+		t.Errorf("aspect = %g, want 1", mask.aspect)
+	}
+
+	// Rectangles scale with the drawn size and are placed at the sprite's position
+	got := mask.rects(10, 20, 48)[1]
+	want1 := collisionRect{x: 18, y: 24, w: 32, h: 4}
+	if math.Abs(got.x-want1.x) > 1e-9 || math.Abs(got.y-want1.y) > 1e-9 ||
+		math.Abs(got.w-want1.w) > 1e-9 || math.Abs(got.h-want1.h) > 1e-9 {
+		t.Errorf("rects(10, 20, 48)[1] = %+v, want %+v", got, want1)
+	}
+}
+
+func TestCollisionMaskTransparent(t *testing.T) {
+	atlas := maskTestAtlas([2][]image.Rectangle{{image.Rect(0, 0, 24, 24)}, nil}, maskAlphaThreshold-1)
+	if mask := newCollisionMask(atlas, "swim"); mask != nil {
+		t.Errorf("newCollisionMask = %+v for a transparent atlas, want nil", mask)
+	}
+	atlas.source = nil
+	if mask := newCollisionMask(atlas, "swim"); mask != nil {
+		t.Errorf("newCollisionMask = %+v without a CPU copy, want nil", mask)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Settings Screen ---
+
+// Settings list layout
+const (
+	settingsScale       = 1.75
+	settingsLabelWidth  = 380.0 // Values line up after the names
+	settingsVisibleRows = 14    // Rows shown at once; the list scrolls to keep the focused row in view
+)
+
+// choice is a settings row that steps through values. The assist options are
+// indented under the Assist mode row that turns them on.
+func choice(key string, value func(g *Game) string, adjust func(g *Game, dir int)) *Choice {
+	c := &Choice{Text: key, Value: value, Adjust: adjust, Scale: settingsScale, LabelWidth: settingsLabelWidth}
+	if strings.HasPrefix(key, "settings.assist.") {
+		c.Indent = 20
+	}
+	return c
+}
+
+// volumeSlider is a settings row for a volume level, in tenths
+func volumeSlider(key string, level func(g *Game) *float64) *Slider {
+	return &Slider{Text: key, Level: level, Steps: 10, Scale: settingsScale, LabelWidth: settingsLabelWidth, BarWidth: 160}
+}
+
+// settingsRows builds the rows of the settings screen, top to bottom. Left/Right
+// step through a row's values; Enter and clicks step forward.
+func settingsRows() []Widget {
+	sfx := volumeSlider("settings.sfxVolume", func(g *Game) *float64 { return &g.settings.SFXVolume })
+	sfx.Preview = func(g *Game) { g.audio.Play(SoundCoin) } // Preview the new level
+
+	// Opens another screen rather than holding a value; indented like the names of the other rows
+	controls := &Button{
+		Content: HStack(0, hgap(rowPadding*2), &Label{Text: "settings.controls", Scale: settingsScale}),
+		Padding: rowPadding,
+		OnPress: func(g *Game) { g.controls = newControlsScreen() },
+	}
+
+	return []Widget{
+		choice("settings.fullscreen", func(g *Game) string { return onOff(g.settings.Fullscreen) }, func(g *Game, dir int) {
+			g.settings.Fullscreen = !g.settings.Fullscreen
+		}),
+		choice("settings.windowScale", func(g *Game) string { return fmt.Sprintf("%gx", g.settings.WindowScale) }, func(g *Game, dir int) {
+			g.settings.WindowScale = cycle(windowScales, g.settings.WindowScale, dir)
+			g.settings.resizeWindow()
+		}),
+		choice("settings.scaling", func(g *Game) string { return tr("scaling." + string(g.settings.ScaleMode)) }, func(g *Game, dir int) {
+			g.settings.ScaleMode = cycle([]ScaleMode{ScaleFit, ScaleInteger}, g.settings.ScaleMode, dir)
+		}),
+		choice("settings.vsync", func(g *Game) string { return onOff(g.settings.VSync) }, func(g *Game, dir int) {
+			g.settings.VSync = !g.settings.VSync
+		}),
+		volumeSlider("settings.masterVolume", func(g *Game) *float64 { return &g.settings.MasterVolume }),
+		volumeSlider("settings.musicVolume", func(g *Game) *float64 { return &g.settings.MusicVolume }),
+		sfx,
+		controls,
+		choice("settings.reducedEffects", func(g *Game) string { return onOff(g.settings.ReducedEffects) }, func(g *Game, dir int) {
+			g.settings.ReducedEffects = !g.settings.ReducedEffects
+		}),
+		choice("settings.language", func(g *Game) string { return languageName(g.settings.Language) }, func(g *Game, dir int) {
+			g.settings.Language = cycle(listLanguages(), g.settings.Language, dir)
+			setLanguage(g.settings.Language)
+		}),
+		choice("settings.assetPack", func(g *Game) string { return g.pack.Name }, func(g *Game, dir int) {
+			g.settings.AssetPack = nextAssetPack(g.pack.ID, dir)
+			g.reloadScene()
+		}),
+		choice("settings.palette", func(g *Game) string { return tr(paletteNames[g.settings.Palette]) }, func(g *Game, dir int) {
+			g.settings.Palette = cycle(palettes, g.settings.Palette, dir)
+			g.reloadScene()
+		}),
+		choice("settings.highContrast", func(g *Game) string { return onOff(g.settings.HighContrast) }, func(g *Game, dir int) {
+			g.settings.HighContrast = !g.settings.HighContrast
+			g.reloadScene()
+		}),
+		choice("settings.collisionZones", func(g *Game) string { return onOff(g.settings.CollisionZones) }, func(g *Game, dir int) {
+			g.settings.CollisionZones = !g.settings.CollisionZones
+		}),
+		choice("settings.reducedMotion", func(g *Game) string { return onOff(g.settings.ReducedMotion) }, func(g *Game, dir int) {
+			g.settings.ReducedMotion = !g.settings.ReducedMotion
+		}),
+		choice("settings.assist", func(g *Game) string { return onOff(g.settings.Assist.Enabled) }, func(g *Game, dir int) {
+			g.settings.Assist.Enabled = !g.settings.Assist.Enabled
+		}),
+		choice("settings.assist.topSpeed", func(g *Game) string { return fmt.Sprintf("%gx", g.settings.Assist.SpeedCap) }, func(g *Game, dir int) {
+			g.settings.Assist.SpeedCap = cycle(assistSpeedCaps, g.settings.Assist.SpeedCap, dir)
+		}),
+		choice("settings.assist.gapScale", func(g *Game) string { return percent(g.settings.Assist.GapScale) }, func(g *Game, dir int) {
+			g.settings.Assist.GapScale = cycle(assistGapScales, g.settings.Assist.GapScale, dir)
+		}),
+		choice("settings.assist.gameSpeed", func(g *Game) string { return percent(g.settings.Assist.GameSpeed) }, func(g *Game, dir int) {
+			g.settings.Assist.GameSpeed = cycle(assistGameSpeeds, g.settings.Assist.GameSpeed, dir)
+		}),
+		choice("settings.assist.invincible", func(g *Game) string { return onOff(g.settings.Assist.Invincible) }, func(g *Game, dir int) {
+			g.settings.Assist.Invincible = !g.settings.Assist.Invincible
+		}),
+		choice("settings.steering", func(g *Game) string { return steeringName(g.settings.PointerSteering) }, func(g *Game, dir int) {
+			g.settings.PointerSteering = !g.settings.PointerSteering
+		}),
+		choice("settings.updateRate", func(g *Game) string { return tr("settings.tps", g.settings.TPS) }, func(g *Game, dir int) {
+			g.settings.TPS = cycle(supportedTPS, g.settings.TPS, dir)
+		}),
+		choice("settings.collisionShape", func(g *Game) string { return tr("collision." + string(g.settings.CollisionMode)) }, func(g *Game, dir int) {
+			g.settings.CollisionMode = cycle([]CollisionMode{CollisionCircle, CollisionMask}, g.settings.CollisionMode, dir)
+		}),
+	}
+}
+
+// settingsScreen is the state of the settings screen
+type settingsScreen struct {
+	ui *UI
+}
+
+// newSettingsScreen opens the settings screen
+func newSettingsScreen() *settingsScreen {
+	rows := settingsRows()
+	position := &Label{
+		widget: widget{Align: AlignEnd},
+		Text:   "%s",
+		Value: func(g *Game) interface{} {
+			return fmt.Sprintf("%d / %d", g.settingsMenu.ui.FocusIndex()+1, len(rows))
+		},
+		Scale:  1.5,
+		Subtle: true,
+	}
+
+	ui := NewUI(&Panel{
+		widget:  widget{MinW: 720, MinH: 680},
+		Padding: 30,
+		Child: VStack(0,
+			HStack(0, &Label{Text: "settings.title", Scale: 3.0}, position),
+			vgap(24),
+			&List{Items: rows, Rows: settingsVisibleRows, Spacing: 4},
+			vgap(24),
+			&Label{Text: "settings.help", Scale: 1.5, Subtle: true},
+		),
+	})
+	ui.OnChange = func(g *Game) {
+		g.settings.apply()
+		g.saveSettings()
+	}
+	ui.OnBack = func(g *Game) { g.settingsMenu = nil }
+	return &settingsScreen{ui: ui}
+}
+
+// updateSettingsScreen handles navigation and changes. Like the controls
+// screen, the arrow keys, Enter and Escape always work here.
+func (g *Game) updateSettingsScreen() {
+	g.settingsMenu.ui.Update(g)
+}
+
+// reloadScene rebuilds the scene after a change to its sprites or colors,
+// staying on the settings screen
+func (g *Game) reloadScene() {
+	g.saveSettings()
+	s := g.settingsMenu
+	g.restart()
+	g.settingsMenu = s
+}
+
+// drawSettingsScreen draws the settings screen over the menu background
+func (g *Game) drawSettingsScreen(screen *ebiten.Image) {
+	g.settingsMenu.ui.Draw(screen, g)
+}
+
+// cycle returns the value dir places after current in values, wrapping around
+// (the first value if current isn't one of them)
+func cycle[T comparable](values []T, current T, dir int) T {
+	i := indexOf(values, current)
+	if i < 0 {
+		return values[0]
+	}
+	return values[(i+dir+len(values))%len(values)]
+}
+
+// onOff formats a toggle for display
+func onOff(on bool) string {
+	if on {
+		return tr("value.on")
+	}
+	return tr("value.off")
+}
+
+// percent formats a 0 to 1 level for display
+func percent(v float64) string {
+	return fmt.Sprintf("%d%%", int(math.Round(v*100)))
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Asset Packs ---
+
+// defaultAssetPack is the ID of the pack shipped with the game
+const defaultAssetPack = "default"
+
+// PackColors is the palette an asset pack supplies to the draw functions
+type PackColors struct {
+	Water         color.RGBA // Background fill
+	CoinBorder    color.RGBA
+	CoinMain      color.RGBA
+	CoinHighlight color.RGBA
+	CoinShadow    color.RGBA
+	BubbleOuter   color.RGBA
+	BubbleInner   color.RGBA
+	KelpDark      color.RGBA // Generated kelp stem center
+	KelpMedium    color.RGBA // Generated kelp stem sides
+	KelpLight     color.RGBA // Generated kelp outer edge
+	KelpAccent    color.RGBA // Generated kelp texture
+	RockTop       color.RGBA // Distant rock ridge (lit)
+	RockBottom    color.RGBA // Distant rock ridge (shadowed)
+	MidWater      color.RGBA // Mid-water coral silhouettes
+	SeaweedDark   color.RGBA // Foreground seaweed
+	SeaweedLight  color.RGBA // Foreground seaweed highlight
+	JellyBell     color.RGBA // Jellyfish bell
+	JellyRim      color.RGBA // Jellyfish bell rim
+	JellyTentacle color.RGBA // Jellyfish tentacles
+	LeaderMarker  color.RGBA // Ring around the leader (accessibility palettes and high contrast only)
+	Outline       color.RGBA // Outline of hazards and coins in high contrast mode
+}
+
+// defaultPackColors is the original palette; packs only need to list the colors they change
+func defaultPackColors() PackColors {
+	return PackColors{
+		Water:         color.RGBA{135, 206, 250, 255}, // Sky Blue (Water/Air)
+		CoinBorder:    color.RGBA{180, 140, 0, 255},   // Dark gold
+		CoinMain:      color.RGBA{255, 215, 0, 255},   // Gold
+		CoinHighlight: color.RGBA{255, 245, 150, 255}, // Bright gold
+		CoinShadow:    color.RGBA{200, 170, 0, 255},   // Darker gold
+		BubbleOuter:   color.RGBA{200, 230, 255, 80},  // Light blue, semi-transparent
+		BubbleInner:   color.RGBA{255, 255, 255, 120}, // White highlight, more opaque
+		KelpDark:      color.RGBA{0, 80, 0, 255},      // Dark green
+		KelpMedium:    color.RGBA{0, 120, 0, 255},     // Medium green
+		KelpLight:     color.RGBA{0, 160, 0, 255},     // Light green
+		KelpAccent:    color.RGBA{20, 100, 20, 255},   // Accent green
+		RockTop:       color.RGBA{70, 110, 150, 255},
+		RockBottom:    color.RGBA{40, 70, 110, 255},
+		MidWater:      color.RGBA{50, 90, 120, 255},
+		SeaweedDark:   color.RGBA{10, 70, 30, 255},
+		SeaweedLight:  color.RGBA{30, 110, 50, 255},
+		JellyBell:     color.RGBA{230, 140, 200, 200}, // Pink, semi-transparent
+		JellyRim:      color.RGBA{250, 190, 230, 230}, // Lighter rim
+		JellyTentacle: color.RGBA{240, 170, 220, 160},
+		LeaderMarker:  color.RGBA{255, 255, 255, 255},
+		Outline:       color.RGBA{255, 255, 255, 255},
+	}
+}
+
+// byName maps manifest color keys to the palette fields they set
+func (c *PackColors) byName() map[string]*color.RGBA {
+	return map[string]*color.RGBA{
+		"water":         &c.Water,
+		"coinBorder":    &c.CoinBorder,
+		"coinMain":      &c.CoinMain,
+		"coinHighlight": &c.CoinHighlight,
+		"coinShadow":    &c.CoinShadow,
+		"bubbleOuter":   &c.BubbleOuter,
+		"bubbleInner":   &c.BubbleInner,
+		"kelpDark":      &c.KelpDark,
+		"kelpMedium":    &c.KelpMedium,
+		"kelpLight":     &c.KelpLight,
+		"kelpAccent":    &c.KelpAccent,
+		"rockTop":       &c.RockTop,
+		"rockBottom":    &c.RockBottom,
+		"midWater":      &c.MidWater,
+		"seaweedDark":   &c.SeaweedDark,
+		"seaweedLight":  &c.SeaweedLight,
+		"jellyBell":     &c.JellyBell,
+		"jellyRim":      &c.JellyRim,
+		"jellyTentacle": &c.JellyTentacle,
+		"leaderMarker":  &c.LeaderMarker,
+		"outline":       &c.Outline,
+	}
+}
+
+// override sets the colors named in colors (manifest keys to hex strings)
+func (c *PackColors) override(colors map[string]string) error {
+	fields := c.byName()
+	for key, value := range colors {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown color %q", key)
+		}
+		rgba, err := parseHexColor(value)
+		if err != nil {
+			return err
+		}
+		*field = rgba
+	}
+	return nil
+}
+
+// parseHexColor parses "#RRGGBB" or "#RRGGBBAA"
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("color %q must be #RRGGBB or #RRGGBBAA", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("color %q: %w", s, err)
+	}
+	return color.RGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// packAtlasRef points at an atlas image and its JSON frame metadata
+type packAtlasRef struct {
+	Image string `json:"image"`
+	Meta  string `json:"meta"`
+}
+
+// packManifest is the pack.json file describing an asset pack. File names are
+// relative to the pack directory, falling back to the assets root.
+type packManifest struct {
+	Name          string            `json:"name"`          // Display name
+	FishSprite    string            `json:"fishSprite"`    // Static fish image (a swim cycle is generated from it)
+	FishAtlas     *packAtlasRef     `json:"fishAtlas"`     // Optional atlas with a "swim" animation, replaces fishSprite
+	KelpSprite    string            `json:"kelpSprite"`    // Optional kelp tile; generated from the kelp colors if empty
+	KelpAtlas     *packAtlasRef     `json:"kelpAtlas"`     // Optional atlas with a "wave" animation, replaces kelpSprite
+	GameOverImage string            `json:"gameOverImage"` // Optional image shown on the game over panel
+	Colors        map[string]string `json:"colors"`        // Palette overrides as hex strings
+}
+
+// AssetPack is a loaded skin: sprites, palette and images used by the draw functions
+type AssetPack struct {
+	ID            string
+	Name          string
+	Colors        PackColors
+	fishAtlas     *SpriteAtlas
+	kelpAtlas     *SpriteAtlas
+	fishMask      *collisionMask // Traced from the swim frames (nil if they couldn't be traced)
+	kelpGenerated bool           // kelpAtlas was drawn from the palette, so it can be redrawn in another palette
+	gameOverImage *ebiten.Image  // nil if the pack has none
+}
+
+// loadedPacks caches packs by ID so restarting doesn't rebuild their sprites
+var loadedPacks = map[string]*AssetPack{}
+
+// loadActivePack returns the pack with the given ID, falling back to the
+// default pack (and finally the built-in sprites) if it can't be loaded
+func loadActivePack(id string) *AssetPack {
+	if pack, ok := loadedPacks[id]; ok {
+		return pack
+	}
+	pack, err := LoadAssetPack(id)
+	if err != nil {
+		log.Printf("Failed to load asset pack %q: %v", id, err)
+		if id != defaultAssetPack {
+			return loadActivePack(defaultAssetPack)
+		}
+		pack = builtinAssetPack()
+	}
+	loadedPacks[id] = pack
+	return pack
+}
+
+// LoadAssetPack reads packs/<id>/pack.json and loads everything it references
+func LoadAssetPack(id string) (*AssetPack, error) {
+	dir := path.Join("packs", id)
+	data, err := readAsset(path.Join(dir, "pack.json"))
+	if err != nil {
+		return nil, err
+	}
+	var m packManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing pack.json: %w", err)
+	}
+
+	pack := &AssetPack{ID: id, Name: m.Name, Colors: defaultPackColors()}
+	if pack.Name == "" {
+		pack.Name = id
+	}
+	if err := pack.Colors.override(m.Colors); err != nil {
+		return nil, err
+	}
+
+	// Fish: an explicit atlas wins, otherwise generate a swim cycle from the sprite
+	if m.FishAtlas != nil {
+		pack.fishAtlas, err = LoadSpriteAtlas(packAsset(dir, m.FishAtlas.Image), packAsset(dir, m.FishAtlas.Meta))
+		if err != nil {
+			return nil, fmt.Errorf("fish atlas: %w", err)
+		}
+		if pack.fishAtlas.Animation("swim") == nil {
+			return nil, fmt.Errorf("fish atlas has no \"swim\" animation")
+		}
+	} else {
+		fishSprite := m.FishSprite
+		if fishSprite == "" {
+			fishSprite = "fish.png"
+		}
+		pack.fishAtlas = createFishSwimAtlas(createFishImage(packAsset(dir, fishSprite)))
+	}
+	pack.fishMask = newCollisionMask(pack.fishAtlas, "swim")
+
+	// Kelp: atlas, single tile, or generated from the palette
+	switch {
+	case m.KelpAtlas != nil:
+		pack.kelpAtlas, err = LoadSpriteAtlas(packAsset(dir, m.KelpAtlas.Image), packAsset(dir, m.KelpAtlas.Meta))
+		if err != nil {
+			return nil, fmt.Errorf("kelp atlas: %w", err)
+		}
+		if pack.kelpAtlas.Animation("wave") == nil {
+			return nil, fmt.Errorf("kelp atlas has no \"wave\" animation")
+		}
+	case m.KelpSprite != "":
+		tile, err := loadImageAsset(packAsset(dir, m.KelpSprite))
+		if err != nil {
+			return nil, fmt.Errorf("kelp sprite: %w", err)
+		}
+		b := tile.Bounds()
+		pack.kelpAtlas, err = newSpriteAtlas(tile, gridAtlasMeta("wave", 1, 1, b.Dx(), b.Dy(), 1))
+		if err != nil {
+			return nil, fmt.Errorf("kelp sprite: %w", err)
+		}
+	default:
+		pack.kelpAtlas = createKelpAtlas(&pack.Colors)
+		pack.kelpGenerated = true
+	}
+
+	// Game over image is optional (won't fail the pack if it doesn't exist)
+	if m.GameOverImage != "" {
+		if img, err := loadImageAsset(packAsset(dir, m.GameOverImage)); err == nil {
+			pack.gameOverImage = img
+		}
+	}
+
+	return pack, nil
+}
+
+// builtinAssetPack is used when even the default pack can't be loaded
+func builtinAssetPack() *AssetPack {
+	pack := &AssetPack{ID: defaultAssetPack, Name: "Built-in", Colors: defaultPackColors()}
+	pack.fishAtlas = createFishSwimAtlas(createFishImage("fish.png"))
+	pack.fishMask = newCollisionMask(pack.fishAtlas, "swim")
+	pack.kelpAtlas = createKelpAtlas(&pack.Colors)
+	pack.kelpGenerated = true
+	return pack
+}
+
+// packAsset resolves a file named in a manifest: the pack directory first, then the assets root
+func packAsset(dir, name string) string {
+	inPack := path.Join(dir, name)
+	if f, err := openAsset(inPack); err == nil {
+		f.Close()
+		return inPack
+	}
+	return name
+}
+
+// listAssetPacks returns the IDs of every pack in the embedded assets and the override directory
+func listAssetPacks() []string {
+	seen := map[string]bool{}
+	addPacks := func(fsys fs.FS, root string) {
+		entries, err := fs.ReadDir(fsys, root)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				if _, err := fs.Stat(fsys, path.Join(root, e.Name(), "pack.json")); err == nil {
+					seen[e.Name()] = true
+				}
+			}
+		}
+	}
+	addPacks(embeddedAssets, "assets/packs")
+	if assetOverrideDir != "" {
+		addPacks(os.DirFS(filepath.Clean(assetOverrideDir)), "packs")
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// nextAssetPack returns the pack dir places after current in listAssetPacks order (wrapping around)
+func nextAssetPack(current string, dir int) string {
+	ids := listAssetPacks()
+	if len(ids) == 0 {
+		return defaultAssetPack
+	}
+	return cycle(ids, current, dir)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Parallax Background ---
+
+// ParallaxLayer is a horizontally repeating strip of scenery that scrolls at a
+// fraction of the current scroll speed to give the world a sense of depth
+type ParallaxLayer struct {
+	sprite      *ebiten.Image // Tileable strip, exactly ScreenWidth pixels wide
+	speedFactor float64       // Fraction of the current scroll speed (1.0 = moves with the kelp)
+	offset      float64       // Current horizontal scroll offset in pixels (0 to ScreenWidth)
+	prevOffset  float64       // offset before the latest simulation step
+	y           float64       // Top edge of the strip on screen
+	alpha       float64       // Opacity used when drawing the strip
+	foreground  bool          // Foreground layers are drawn in front of the school
+}
+
+// Parallax layer indices (ordered back to front)
+const (
+	LayerDistantRocks = iota
+	LayerMidWater
+	LayerForegroundSeaweed
+)
+
+// newParallaxLayers builds the scenery layers, back to front, in the pack's colors
+func newParallaxLayers(colors *PackColors) []*ParallaxLayer {
+	rocks := createRockSprite(colors)
+	midWater := createMidWaterSprite(colors)
+	seaweed := createSeaweedSprite(colors)
+
+	_, rocksH := rocks.Size()
+	_, midH := midWater.Size()
+	_, seaweedH := seaweed.Size()
+
+	return []*ParallaxLayer{
+		LayerDistantRocks: {
+			sprite:      rocks,
+			speedFactor: 0.15,
+			y:           float64(ScreenHeight - rocksH),
+			alpha:       0.6,
+		},
+		LayerMidWater: {
+			sprite:      midWater,
+			speedFactor: 0.4,
+			y:           float64(ScreenHeight - midH),
+			alpha:       0.7,
+		},
+		LayerForegroundSeaweed: {
+			sprite:      seaweed,
+			speedFactor: 1.4, // Faster than the kelp so it reads as closer to the camera
+			y:           float64(ScreenHeight - seaweedH),
+			alpha:       0.85,
+			foreground:  true,
+		},
+	}
+}
+
+// update scrolls the layer by its share of the current scroll speed
+func (l *ParallaxLayer) update(scrollSpeed float64) {
+	l.offset = math.Mod(l.offset+scrollSpeed*l.speedFactor, ScreenWidth)
+}
+
+// interpolatedOffset blends the offset before and after the latest step,
+// unwrapping it when the step carried it past ScreenWidth
+func (l *ParallaxLayer) interpolatedOffset(alpha float64) float64 {
+	delta := l.offset - l.prevOffset
+	if delta < 0 {
+		delta += ScreenWidth
+	}
+	return math.Mod(l.prevOffset+delta*alpha, ScreenWidth)
+}
+
+// draw repeats the strip across the screen (one extra copy so the seam is never visible)
+func (l *ParallaxLayer) draw(screen *ebiten.Image, alpha float64) {
+	offset := l.interpolatedOffset(alpha)
+	copies := int(math.Ceil(float64(screen.Bounds().Dx())/ScreenWidth)) + 1
+	for i := 0; i < copies; i++ {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-offset+float64(i*ScreenWidth), l.y)
+		op.ColorScale.ScaleAlpha(float32(l.alpha))
+		screen.DrawImage(l.sprite, op)
+	}
+}
+
+// layerForDepth maps a background fish depth (0.3 to 0.7) to the layer it
+// swims in, so that distant fish drift with the rocks and nearer ones with the mid-water
+func layerForDepth(depth float64) int {
+	if depth < 0.5 {
+		return LayerDistantRocks
+	}
+	return LayerMidWater
+}
+
+// drawBackgroundLayers draws every layer behind the school, interleaving the
+// background fish that belong to each layer (unless effects or motion are reduced)
+func (g *Game) drawBackgroundLayers(screen *ebiten.Image) {
+	for i, layer := range g.parallaxLayers {
+		if layer.foreground {
+			continue
+		}
+		layer.draw(screen, g.stepAlpha())
+		if g.settings.ReducedEffects || g.settings.ReducedMotion {
+			continue
+		}
+		for _, bgFish := range g.backgroundFish {
+			if bgFish.layer == i {
+				g.drawBackgroundFish(screen, bgFish)
+			}
+		}
+	}
+}
+
+// drawForegroundLayers draws the layers that pass in front of the school
+func (g *Game) drawForegroundLayers(screen *ebiten.Image) {
+	for _, layer := range g.parallaxLayers {
+		if layer.foreground {
+			layer.draw(screen, g.stepAlpha())
+		}
+	}
+}
+
+// --- Parallax Sprite Creation ---
+
+// tileableWave sums sine waves whose periods divide ScreenWidth, so the
+// resulting height profile wraps seamlessly at the strip edges
+func tileableWave(x float64, harmonics []float64, amplitudes []float64, phases []float64) float64 {
+	v := 0.0
+	for i := range harmonics {
+		v += math.Sin(2*math.Pi*harmonics[i]*x/ScreenWidth+phases[i]) * amplitudes[i]
+	}
+	return v
+}
+
+// createRockSprite creates the distant rock ridge silhouette
+func createRockSprite(colors *PackColors) *ebiten.Image {
+	width := ScreenWidth
+	height := 220
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	rockTop := colors.RockTop       // Lit ridge line
+	rockBottom := colors.RockBottom // Shadowed base
+
+	for x := 0; x < width; x++ {
+		// Rolling ridge with a few sharper peaks
+		ridge := 120 + tileableWave(float64(x), []float64{2, 5, 11}, []float64{40, 22, 8}, []float64{0.3, 1.7, 4.1})
+		top := height - int(ridge)
+		for y := top; y < height; y++ {
+			if y < 0 {
+				continue
+			}
+			// Blend from lit top to shadowed bottom
+			t := float64(y-top) / float64(height-top)
+			setPixel(img, x, y, lerpColor(rockTop, rockBottom, t))
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// createMidWaterSprite creates coral pillars and arches seen through the mid-water haze
+func createMidWaterSprite(colors *PackColors) *ebiten.Image {
+	width := ScreenWidth
+	height := 300
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	coral := colors.MidWater // Hazy blue-grey silhouette
+
+	for x := 0; x < width; x++ {
+		// Low base with tall spires where the two waves line up
+		base := 60 + tileableWave(float64(x), []float64{3, 7}, []float64{20, 10}, []float64{2.2, 0.4})
+		spire := tileableWave(float64(x), []float64{16, 23}, []float64{1, 1}, []float64{0, 1.3})
+		if spire > 1.2 {
+			base += (spire - 1.2) * 260
+		}
+		top := height - int(base)
+		for y := top; y < height; y++ {
+			if y < 0 {
+				continue
+			}
+			setPixel(img, x, y, coral)
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// createSeaweedSprite creates a band of foreground seaweed fronds
+func createSeaweedSprite(colors *PackColors) *ebiten.Image {
+	width := ScreenWidth
+	height := 110
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	frondDark := colors.SeaweedDark
+	frondLight := colors.SeaweedLight
+
+	// Fronds are evenly spaced so the strip tiles cleanly
+	frondSpacing := 40
+	for base := 0; base < width; base += frondSpacing {
+		frondHeight := 50 + int(30*(1+math.Sin(float64(base)*0.37)))
+		for y := 0; y < frondHeight; y++ {
+			// Fronds lean and curl more toward their tips
+			t := float64(y) / float64(frondHeight)
+			sway := math.Sin(t*3+float64(base)) * 8 * t
+			halfWidth := int(4*(1-t) + 1)
+			cx := base + int(sway)
+			for dx := -halfWidth; dx <= halfWidth; dx++ {
+				px := (cx + dx + width) % width
+				col := frondDark
+				if dx < 0 {
+					col = frondLight
+				}
+				setPixel(img, px, height-1-y, col)
+			}
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// lerpColor linearly interpolates between two opaque colors
+func lerpColor(a, b color.RGBA, t float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(a.R) + (float64(b.R)-float64(a.R))*t),
+		G: uint8(float64(a.G) + (float64(b.G)-float64(a.G))*t),
+		B: uint8(float64(a.B) + (float64(b.B)-float64(a.B))*t),
+		A: 255,
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Particles ---
+
+// An Emitter owns a fixed pool of particles that all follow one
+// EmitterConfig. Live particles are packed at the front of the pool, so
+// spawning and expiring never allocate, and the whole pool is drawn with a
+// single DrawTriangles call that tints the shared circle sprite. Particles
+// are cosmetic, so they use the shared generator rather than the run's seed.
+
+// Curve is a value over a particle's life, as evenly spaced keyframes from
+// birth to death that are blended linearly (nil is 1 throughout)
+type Curve []float64
+
+// at samples the curve at t, from 0 (birth) to 1 (death)
+func (c Curve) at(t float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	if len(c) == 1 || t <= 0 {
+		return c[0]
+	}
+	if t >= 1 {
+		return c[len(c)-1]
+	}
+	pos := t * float64(len(c)-1)
+	i := int(pos)
+	return c[i] + (c[i+1]-c[i])*(pos-float64(i))
+}
+
+// EmitterConfig describes how an emitter's particles are born, move and fade.
+// Speeds and accelerations are per simulation step, like the rest of the game.
+type EmitterConfig struct {
+	Life         [2]int     // Lifetime range in steps (0 lives until it wraps, for ambient particles)
+	Speed        [2]float64 // Launch speed range
+	Angle        float64    // Launch direction in radians (0 is right, Pi/2 is down)
+	Spread       float64    // Launch directions vary up to this far either side of Angle
+	Size         [2]float64 // Radius range
+	Gravity      float64    // Downward acceleration (negative rises, like buoyancy)
+	Drag         float64    // Fraction of velocity lost each step
+	ScrollFactor float64    // How much of the scroll speed carries the particle left (1 moves with the kelp)
+	Wobble       float64    // Horizontal sway per step
+	WobbleSpeed  [2]float64 // Sway phase advance range per step
+	Wrap         bool       // Particles that rise off the top come back at the bottom
+
+	Color     color.RGBA // Tint at birth
+	EndColor  color.RGBA // Tint at death, blended from Color (zero keeps Color)
+	Alpha     Curve      // Opacity over the particle's life
+	Scale     Curve      // Size over the particle's life
+	Highlight color.RGBA // Smaller circle drawn up and to the left, like a bubble's shine (zero for none)
+}
+
+// particle is one pooled particle
+type particle struct {
+	x, y         float64
+	prevX, prevY float64 // Position before the latest simulation step
+	vx, vy       float64
+	size         float64
+	age, life    int
+	wobble       float64
+	wobbleSpeed  float64
+}
+
+// Emitter spawns, moves and draws one kind of particle
+type Emitter struct {
+	Config EmitterConfig
+
+	pool []particle // Live particles first, then free slots
+	live int
+
+	// Reused by every draw
+	vertices []ebiten.Vertex
+	indices  []uint32
+}
+
+// NewEmitter makes an emitter that can hold capacity particles at once
+func NewEmitter(config EmitterConfig, capacity int) *Emitter {
+	return &Emitter{Config: config, pool: make([]particle, capacity)}
+}
+
+// Len is the number of live particles
+func (e *Emitter) Len() int {
+	return e.live
+}
+
+// randRange returns a value between r[0] and r[1]
+func randRange(r [2]float64) float64 {
+	return r[0] + rand.Float64()*(r[1]-r[0])
+}
+
+// Emit spawns n particles at a point. Particles beyond the pool's capacity are dropped.
+func (e *Emitter) Emit(x, y float64, n int) {
+	e.EmitArea(x, y, 0, 0, n)
+}
+
+// EmitArea spawns n particles at random points in a rectangle
+func (e *Emitter) EmitArea(x, y, w, h float64, n int) {
+	cfg := &e.Config
+	for ; n > 0 && e.live < len(e.pool); n-- {
+		angle := cfg.Angle + (rand.Float64()*2-1)*cfg.Spread
+		speed := randRange(cfg.Speed)
+		p := particle{
+			x:           x + rand.Float64()*w,
+			y:           y + rand.Float64()*h,
+			vx:          math.Cos(angle) * speed,
+			vy:          math.Sin(angle) * speed,
+			size:        randRange(cfg.Size),
+			wobble:      rand.Float64() * 2 * math.Pi, // Random starting phase
+			wobbleSpeed: randRange(cfg.WobbleSpeed),
+		}
+		if cfg.Life[1] > 0 {
+			p.life = cfg.Life[0] + rand.Intn(cfg.Life[1]-cfg.Life[0]+1)
+		}
+		p.prevX, p.prevY = p.x, p.y
+		e.pool[e.live] = p
+		e.live++
+	}
+}
+
+// update advances every particle by one step and expires the old ones.
+// scroll is how far the world scrolled left this step.
+func (e *Emitter) update(scroll float64, reducedMotion bool) {
+	cfg := &e.Config
+	for i := 0; i < e.live; {
+		p := &e.pool[i]
+		p.age++
+		if p.life > 0 && p.age >= p.life {
+			// Expire by moving the last live particle into this slot
+			e.live--
+			e.pool[i] = e.pool[e.live]
+			continue
+		}
+
+		p.vy += cfg.Gravity
+		p.vx *= 1 - cfg.Drag
+		p.vy *= 1 - cfg.Drag
+		p.x += p.vx - scroll*cfg.ScrollFactor
+		p.y += p.vy
+
+		// Sway side to side, unless motion is reduced
+		if cfg.Wobble != 0 && !reducedMotion {
+			p.wobble += p.wobbleSpeed
+			p.x += math.Sin(p.wobble) * cfg.Wobble
+		}
+
+		if cfg.Wrap {
+			e.wrap(p)
+		}
+		i++
+	}
+}
+
+// wrap brings an ambient particle that left the screen back on the other side
+func (e *Emitter) wrap(p *particle) {
+	// Off the top: reset to the bottom at a new random x position
+	if p.y < -p.size {
+		p.y = ScreenHeight + p.size
+		p.x = rand.Float64() * display.worldWidth()
+		p.wobble = rand.Float64() * 2 * math.Pi
+		p.prevX, p.prevY = p.x, p.y // Don't interpolate across the jump
+	}
+
+	// Off either side (with some slack for wobble)
+	if p.x < -20 {
+		p.x = display.worldWidth() + 20
+		p.prevX = p.x
+	} else if p.x > display.worldWidth()+20 {
+		p.x = -20
+		p.prevX = p.x
+	}
+}
+
+// savePrevious records where the particles are before a step moves them
+func (e *Emitter) savePrevious() {
+	for i := range e.pool[:e.live] {
+		p := &e.pool[i]
+		p.prevX, p.prevY = p.x, p.y
+	}
+}
+
+// draw draws every live particle in one batch, alpha of the way from their
+// previous to their current positions
+func (e *Emitter) draw(screen *ebiten.Image, alpha float64) {
+	if e.live == 0 {
+		return
+	}
+	if circleSprite == nil {
+		circleSprite = createCircleSprite()
+	}
+	cfg := &e.Config
+	endColor := cfg.EndColor
+	if endColor == (color.RGBA{}) {
+		endColor = cfg.Color
+	}
+
+	e.vertices = e.vertices[:0]
+	e.indices = e.indices[:0]
+	for i := range e.pool[:e.live] {
+		p := &e.pool[i]
+		t := 0.0
+		if p.life > 0 {
+			t = float64(p.age) / float64(p.life)
+		}
+		x := p.prevX + (p.x-p.prevX)*alpha
+		y := p.prevY + (p.y-p.prevY)*alpha
+		r := p.size * cfg.Scale.at(t)
+		fade := cfg.Alpha.at(t)
+
+		clr := lerpColor(cfg.Color, endColor, t) // Opaque, so blend the alphas too
+		clr.A = uint8(float64(cfg.Color.A) + (float64(endColor.A)-float64(cfg.Color.A))*t)
+		e.appendCircle(x, y, r, clr, fade)
+		if cfg.Highlight.A > 0 {
+			e.appendCircle(x-r*0.25, y-r*0.25, r*0.4, cfg.Highlight, fade)
+		}
+	}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.Filter = ebiten.FilterLinear
+	screen.DrawTriangles32(e.vertices, e.indices, circleSprite, op)
+}
+
+// appendCircle adds a quad of the circle sprite, tinted and faded, to the batch
+func (e *Emitter) appendCircle(cx, cy, r float64, clr color.RGBA, fade float64) {
+	base := uint32(len(e.vertices))
+	cr, cg, cb := float32(clr.R)/255, float32(clr.G)/255, float32(clr.B)/255
+	ca := float32(float64(clr.A) / 255 * fade)
+	const s = circleSpriteRadius * 2
+	for _, corner := range [4][2]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		e.vertices = append(e.vertices, ebiten.Vertex{
+			DstX:   float32(cx-r) + corner[0]*float32(r*2),
+			DstY:   float32(cy-r) + corner[1]*float32(r*2),
+			SrcX:   corner[0] * s,
+			SrcY:   corner[1] * s,
+			ColorR: cr,
+			ColorG: cg,
+			ColorB: cb,
+			ColorA: ca,
+		})
+	}
+	e.indices = append(e.indices, base, base+1, base+2, base+1, base+3, base+2)
+}
+
+// --- Game Effects ---
+
+// Pool sizes of the game's emitters
+const (
+	maxWakeParticles    = 512
+	maxSparkleParticles = 1024
+	maxBurstParticles   = 1024
+	maxDebrisParticles  = 512
+)
+
+// Particles spawned by each event
+const (
+	wakePerStep     = 1  // Behind the leader, every step
+	sparklesPerCoin = 14 // When a coin is collected
+	burstParticles  = 60 // Where the school hits a hazard
+	debrisParticles = 24 // Extra kelp fragments when the hazard is kelp
+)
+
+// particleEffects are the game's emitters
+type particleEffects struct {
+	bubbles  *Emitter // Ambient bubbles rising in the background
+	wake     *Emitter // Trail of small bubbles behind the leader
+	sparkles *Emitter // Coin pickups
+	bursts   *Emitter // Bubbles bursting from a collision
+	debris   *Emitter // Kelp fragments knocked loose by a collision
+}
+
+// newParticleEffects builds the emitters in the scene's colors and fills the
+// water with ambient bubbles
+func newParticleEffects(colors *PackColors) *particleEffects {
+	fx := &particleEffects{
+		// Rising at various speeds, swaying 10 pixels left and right
+		bubbles: NewEmitter(EmitterConfig{
+			Speed:       [2]float64{0.5, 2.0},
+			Angle:       -math.Pi / 2,
+			Size:        [2]float64{3, 11},
+			Wobble:      0.5,
+			WobbleSpeed: [2]float64{0.02, 0.05},
+			Wrap:        true,
+			Color:       colors.BubbleOuter,
+			Highlight:   colors.BubbleInner,
+		}, NumBubbles),
+		// Left behind in the water as the school swims on
+		wake: NewEmitter(EmitterConfig{
+			Life:         [2]int{30, 50},
+			Speed:        [2]float64{0.2, 0.8},
+			Angle:        math.Pi,
+			Spread:       0.6,
+			Size:         [2]float64{1.5, 3.5},
+			Gravity:      -0.02,
+			Drag:         0.04,
+			ScrollFactor: 0.6,
+			Color:        colors.BubbleInner,
+			Alpha:        Curve{1, 0.6, 0},
+		}, maxWakeParticles),
+		sparkles: NewEmitter(EmitterConfig{
+			Life:         [2]int{20, 35},
+			Speed:        [2]float64{1.5, 4},
+			Spread:       math.Pi,
+			Size:         [2]float64{2, 4},
+			Drag:         0.1,
+			ScrollFactor: 1,
+			Color:        colors.CoinHighlight,
+			EndColor:     colors.CoinMain,
+			Alpha:        Curve{1, 1, 0},
+			Scale:        Curve{1, 0.3},
+		}, maxSparkleParticles),
+		bursts: NewEmitter(EmitterConfig{
+			Life:      [2]int{40, 80},
+			Speed:     [2]float64{2, 6},
+			Spread:    math.Pi,
+			Size:      [2]float64{2, 7},
+			Gravity:   -0.06,
+			Drag:      0.06,
+			Color:     colors.BubbleOuter,
+			Highlight: colors.BubbleInner,
+			Alpha:     Curve{1, 1, 0},
+		}, maxBurstParticles),
+		// Sinking slowly, tumbling from the kelp's light to dark green
+		debris: NewEmitter(EmitterConfig{
+			Life:     [2]int{60, 100},
+			Speed:    [2]float64{1, 4},
+			Angle:    -math.Pi / 2,
+			Spread:   math.Pi / 2,
+			Size:     [2]float64{2, 5},
+			Gravity:  0.08,
+			Drag:     0.05,
+			Color:    colors.KelpLight,
+			EndColor: colors.KelpDark,
+			Alpha:    Curve{1, 1, 0},
+		}, maxDebrisParticles),
+	}
+	fx.bubbles.EmitArea(0, 0, display.worldWidth(), ScreenHeight, NumBubbles)
+	return fx
+}
+
+// all lists the emitters
+func (fx *particleEffects) all() []*Emitter {
+	return []*Emitter{fx.bubbles, fx.wake, fx.sparkles, fx.bursts, fx.debris}
+}
+
+// Len is the number of live particles across every emitter
+func (fx *particleEffects) Len() int {
+	n := 0
+	for _, e := range fx.all() {
+		n += e.Len()
+	}
+	return n
+}
+
+// update advances every emitter by one step
+func (fx *particleEffects) update(scroll float64, settings *Settings) {
+	for _, e := range fx.all() {
+		e.update(scroll, settings.ReducedMotion)
+	}
+}
+
+// savePrevious records every particle's position before a step
+func (fx *particleEffects) savePrevious() {
+	for _, e := range fx.all() {
+		e.savePrevious()
+	}
+}
+
+// emitWake trails bubbles from the leader's tail. The wake is decorative
+// motion, so it's left out with reduced effects or reduced motion.
+func (g *Game) emitWake() {
+	if g.settings.ReducedEffects || g.settings.ReducedMotion {
+		return
+	}
+	g.effects.wake.Emit(PlayerX+PlayerSize*0.1, g.playerY+PlayerSize/2, wakePerStep)
+}
+
+// emitSparkles scatters sparkles from a collected coin's center
+func (g *Game) emitSparkles(x, y float64) {
+	g.effects.sparkles.Emit(x, y, sparklesPerCoin)
+}
+
+// emitBurst bursts bubbles from where the school hit a hazard, and knocks
+// fragments loose if it was kelp
+func (g *Game) emitBurst(x, y float64, kelp bool) {
+	g.effects.bursts.Emit(x, y, burstParticles)
+	if kelp {
+		g.effects.debris.Emit(x, y, debrisParticles)
+	}
+}
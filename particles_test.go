@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Curves ---
+
+func TestCurveAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve Curve
+		t     float64
+		want  float64
+	}{
+		{"nil is 1", nil, 0.5, 1},
+		{"nil at birth", nil, 0, 1},
+		{"one key is constant", Curve{0.4}, 0, 0.4},
+		{"one key at death", Curve{0.4}, 1, 0.4},
+		{"birth", Curve{1, 0.6, 0}, 0, 1},
+		{"death", Curve{1, 0.6, 0}, 1, 0},
+		{"middle key", Curve{1, 0.6, 0}, 0.5, 0.6},
+		{"between first keys", Curve{1, 0.6, 0}, 0.25, 0.8},
+		{"between last keys", Curve{1, 0.6, 0}, 0.75, 0.3},
+		{"two keys", Curve{1, 0.3}, 0.5, 0.65},
+		{"before birth clamps", Curve{1, 0.3}, -0.5, 1},
+		{"after death clamps", Curve{1, 0.3}, 1.5, 0.3},
+	}
+	for _, tt := range tests {
+		if got := tt.curve.at(tt.t); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: %v.at(%g) = %g, want %g", tt.name, tt.curve, tt.t, got, tt.want)
+		}
+	}
+}
+
+// --- Emitter Pool ---
+
+func TestEmitterExpiry(t *testing.T) {
+	const n = 50
+	e := NewEmitter(EmitterConfig{Life: [2]int{1, 1}}, n)
+	e.Emit(0, 0, n)
+	// Give every particle its own lifetime, and use its size as an ID
+	for i := range e.pool[:e.live] {
+		e.pool[i].life = 1 + i%7
+		e.pool[i].size = float64(i)
+	}
+
+	for step := 1; step <= 8; step++ {
+		e.update(0, false)
+
+		want := 0
+		for i := 0; i < n; i++ {
+			if 1+i%7 > step {
+				want++
+			}
+		}
+		if e.Len() != want {
+			t.Fatalf("after %d steps: %d live particles, want %d", step, e.Len(), want)
+		}
+		seen := map[float64]bool{}
+		for _, p := range e.pool[:e.live] {
+			id := int(p.size)
+			switch {
+			case seen[p.size]:
+				t.Errorf("after %d steps: particle %d is live twice", step, id)
+			case p.life <= step:
+				t.Errorf("after %d steps: particle %d should have expired (life %d)", step, id, p.life)
+			case p.age != step:
+				// A particle moved into an expired slot must still be aged this step
+				t.Errorf("after %d steps: particle %d has age %d", step, id, p.age)
+			}
+			seen[p.size] = true
+		}
+	}
+}
+
+func TestEmitterCapacity(t *testing.T) {
+	e := NewEmitter(EmitterConfig{Life: [2]int{2, 2}}, 10)
+	e.Emit(0, 0, 6)
+	e.Emit(0, 0, 6)
+	if e.Len() != 10 {
+		t.Fatalf("emitting 12 into a pool of 10 left %d live, want 10", e.Len())
+	}
+	e.EmitArea(0, 0, 100, 100, 5)
+	if e.Len() != 10 {
+		t.Fatalf("emitting into a full pool left %d live, want 10", e.Len())
+	}
+	if len(e.pool) != 10 {
+		t.Fatalf("pool grew to %d", len(e.pool))
+	}
+
+	// Once they expire, the slots are reused
+	e.update(0, false)
+	e.update(0, false)
+	if e.Len() != 0 {
+		t.Fatalf("%d particles outlived their life", e.Len())
+	}
+	e.Emit(0, 0, 3)
+	if e.Len() != 3 {
+		t.Errorf("emitting 3 into an empty pool left %d live, want 3", e.Len())
+	}
+}
+
+// --- Particle Benchmarks ---
+
+// fullParticleEffects returns the game's emitters with every pool topped up
+// to capacity, spread over the view like a busy moment in a run
+func fullParticleEffects() *particleEffects {
+	colors := defaultPackColors()
+	fx := newParticleEffects(&colors)
+	fillParticleEffects(fx)
+	return fx
+}
+
+// fillParticleEffects tops up every emitter; Emit drops what doesn't fit
+func fillParticleEffects(fx *particleEffects) {
+	for _, e := range fx.all() {
+		e.EmitArea(0, 0, ScreenWidth, ScreenHeight, len(e.pool))
+	}
+}
+
+// BenchmarkParticlesUpdate steps every emitter at full capacity, topping the
+// pools back up each iteration as particles expire
+func BenchmarkParticlesUpdate(b *testing.B) {
+	fx := fullParticleEffects()
+	settings := defaultSettings()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		fx.savePrevious()
+		fx.update(PlayerSpeed, settings)
+		fillParticleEffects(fx)
+	}
+	b.ReportMetric(float64(fx.Len()), "particles")
+}
+
+// BenchmarkParticlesDraw draws every emitter at full capacity. Like the
+// bubble benchmarks, it reads a pixel back each iteration so the GPU work
+// is timed, not just queueing the draw commands.
+func BenchmarkParticlesDraw(b *testing.B) {
+	fx := fullParticleEffects()
+	screen := ebiten.NewImage(ScreenWidth, ScreenHeight)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, e := range fx.all() {
+			e.draw(screen, 0.5)
+		}
+		screen.At(0, 0)
+	}
+	b.ReportMetric(float64(fx.Len()), "particles")
+}
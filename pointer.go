@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// --- Mouse and Touch Input ---
+
+// pointerSteeringEase is the fraction of the remaining distance the leader
+// covers each tick when steering toward the pointer (before the PlayerSpeed cap)
+const pointerSteeringEase = 0.2
+
+// pointerState tracks the mouse cursor and the first active touch
+type pointerState struct {
+	x, y       float64 // Last known pointer position in logical pixels
+	moved      bool    // The position changed this tick
+	touching   bool    // A finger is on the screen this tick
+	tapped     bool    // The left mouse button or a touch went down this tick
+	touchBegan bool    // A touch (not the mouse) went down this tick
+	touchIDs   []ebiten.TouchID
+}
+
+// update samples the mouse and touches. Touches take priority over the mouse
+// so that touch devices that also report a cursor behave consistently.
+func (p *pointerState) update() {
+	prevX, prevY := p.x, p.y
+	p.touchIDs = ebiten.AppendTouchIDs(p.touchIDs[:0])
+	p.touching = len(p.touchIDs) > 0
+	p.touchBegan = len(inpututil.AppendJustPressedTouchIDs(nil)) > 0
+
+	if p.touching {
+		x, y := ebiten.TouchPosition(p.touchIDs[0])
+		p.x, p.y = display.toLogical(float64(x), float64(y))
+	} else {
+		x, y := ebiten.CursorPosition()
+		p.x, p.y = display.toLogical(float64(x), float64(y))
+	}
+	p.tapped = p.touchBegan || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	p.moved = p.x != prevX || p.y != prevY
+}
+
+// clickRegion is a rectangle on screen that responds to clicks and taps, in
+// logical coordinates (widgets are laid out where they are drawn)
+type clickRegion struct {
+	x, y, w, h float64
+}
+
+// contains reports whether the point lies inside the region
+func (r clickRegion) contains(px, py float64) bool {
+	return px >= r.x && px < r.x+r.w && py >= r.y && py < r.y+r.h
+}
+
+// Pointer returns the pointer position in logical pixels
+func (in *Input) Pointer() (float64, float64) {
+	return in.pointer.x, in.pointer.y
+}
+
+// Tapped reports whether the mouse was clicked or the screen touched inside the region this tick
+func (in *Input) Tapped(r clickRegion) bool {
+	return in.pointer.tapped && r.contains(in.pointer.x, in.pointer.y)
+}
+
+// Hovered reports whether the pointer moved onto or within the region this
+// tick (a resting cursor doesn't fight keyboard navigation)
+func (in *Input) Hovered(r clickRegion) bool {
+	return in.pointer.moved && r.contains(in.pointer.x, in.pointer.y)
+}
+
+// TouchBegan reports whether a finger went down this tick
+func (in *Input) TouchBegan() bool {
+	return in.pointer.touchBegan
+}
+
+// steerTowardPointer moves the leader's center toward the pointer's vertical
+// position, easing in and capped at PlayerSpeed per tick
+func (g *Game) steerTowardPointer() {
+	_, py := g.camera.toWorld(g.input.Pointer())
+	targetY := py - PlayerSize/2
+	step := (targetY - g.playerY) * pointerSteeringEase
+	if step > PlayerSpeed {
+		step = PlayerSpeed
+	} else if step < -PlayerSpeed {
+		step = -PlayerSpeed
+	}
+	g.playerY += step
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"image/color"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Screens ---
+
+// The difficulty menu, pause overlay and game over panel, declared with the
+// UI toolkit (see ui.go)
+
+// newDifficultyMenuUI builds the difficulty selection menu
+func newDifficultyMenuUI() *UI {
+	option := func(key, info string, clr color.Color, d Difficulty, keys ...ebiten.Key) *Button {
+		return &Button{
+			Content: VStack(8,
+				&Label{Text: key, Scale: 2.5, Color: clr},
+				HStack(0, hgap(40), &Label{Text: info, Scale: 1.5, Subtle: true}),
+			),
+			Padding: 10,
+			OnPress: func(g *Game) { g.startRun(d) },
+			Keys:    keys,
+		}
+	}
+	footer := func(key string, value func(g *Game) interface{}, onPress func(g *Game), k ebiten.Key) *Button {
+		b := textButton(key, 1.5, onPress, k)
+		b.Content.(*Label).Value = value
+		b.Content.(*Label).Subtle = true
+		b.MinW = 300
+		return b
+	}
+
+	options := VStack(10,
+		option("menu.easy", "menu.easyInfo", color.RGBA{100, 255, 100, 255}, DifficultyEasy, ebiten.Key1, ebiten.KeyE),
+		option("menu.medium", "menu.mediumInfo", color.RGBA{255, 255, 100, 255}, DifficultyMedium, ebiten.Key2, ebiten.KeyM),
+		option("menu.hard", "menu.hardInfo", color.RGBA{255, 100, 100, 255}, DifficultyHard, ebiten.Key3, ebiten.KeyH),
+	)
+	options.Align = AlignStretch
+
+	return NewUI(&Panel{
+		widget:  widget{MinW: 600},
+		Padding: 40,
+		Child: VStack(0,
+			&Label{widget: widget{Align: AlignCenter}, Text: "menu.title", Scale: 3.0},
+			vgap(40),
+			options,
+			vgap(30),
+			HStack(0,
+				// Switch to the next asset pack and rebuild the scene with it
+				footer("menu.pack", func(g *Game) interface{} { return g.pack.Name }, func(g *Game) {
+					g.settings.AssetPack = nextAssetPack(g.pack.ID, 1)
+					g.saveSettings()
+					g.restart()
+				}, ebiten.KeyP),
+				footer("menu.controls", nil, func(g *Game) { g.controls = newControlsScreen() }, ebiten.KeyC),
+			),
+			HStack(0,
+				// Toggle between key/gamepad and mouse/touch steering
+				footer("menu.steering", func(g *Game) interface{} { return steeringName(g.settings.PointerSteering) }, func(g *Game) {
+					g.settings.PointerSteering = !g.settings.PointerSteering
+					g.saveSettings()
+				}, ebiten.KeyT),
+				footer("menu.settings", nil, func(g *Game) { g.settingsMenu = newSettingsScreen() }, ebiten.KeyO),
+			),
+		),
+	})
+}
+
+// steeringName names a steering mode for display
+func steeringName(pointer bool) string {
+	if pointer {
+		return tr("steering.pointer")
+	}
+	return tr("steering.keys")
+}
+
+// startRun leaves the menu and starts playing at a difficulty
+func (g *Game) startRun(d Difficulty) {
+	g.difficulty = d
+	g.gameStarted = true
+}
+
+// newPauseUI builds the pause overlay
+func newPauseUI() *UI {
+	ui := NewUI(&Stack{
+		Spacing: 10,
+		Align:   AlignCenter,
+		Children: []Widget{
+			&Label{Text: "pause.title", Scale: 4.0},
+			vgap(20),
+			&Button{
+				Content: &Label{Text: "pause.resume", Value: func(g *Game) interface{} { return keyNames(g.input.Keys(ActionPause)) }, Scale: 1.5, Subtle: true},
+				Padding: 6,
+				OnPress: func(g *Game) { g.paused = false },
+			},
+			// The only way out of an invincible run. Q is in reservedKeys, so
+			// no action can be bound to it and end the run by accident.
+			textButton("pause.endRun", 1.5, (*Game).endRun, ebiten.KeyQ),
+		},
+	})
+	ui.Overlay = color.RGBA{0, 0, 0, 120}
+	return ui
+}
+
+// endRun ends the run from the pause screen as if the school had been hit
+func (g *Game) endRun() {
+	g.paused = false
+	g.gameOver = true
+	g.recordRun()
+}
+
+// newGameOverUI builds the game over panel
+func newGameOverUI() *UI {
+	// Gamepads and touch screens can't type the restart code, so the gamepad
+	// Confirm button (see Update) and this button restart directly
+	restart := textButton("gameover.tapRestart", 2.0, (*Game).restart)
+	restart.Filled = true
+	restart.Padding = 10
+	restart.Visible = func(g *Game) bool { return g.settings.PointerSteering }
+
+	stats := VStack(18,
+		&Label{Text: "gameover.title", Scale: 3.0},
+		&Label{Text: "gameover.score", Value: func(g *Game) interface{} { return g.score }, Scale: 2.0},
+		&Label{Text: "gameover.coins", Value: func(g *Game) interface{} { return g.coinsCollected }, Scale: 2.0},
+		&Label{Text: "%s", Value: func(g *Game) interface{} { return g.bestScoreText() }, Scale: 1.5, Color: color.RGBA{255, 215, 0, 255}},
+		vgap(10),
+		&Label{Text: "gameover.restartHint", Scale: 1.5},
+		&Label{widget: widget{Visible: func(g *Game) bool { return g.input.GamepadConnected() }}, Text: "gameover.gamepadHint", Scale: 1.5},
+		&TextInput{Text: "gameover.input", MaxLen: 10, Accept: restartCodeChar, OnSubmit: submitRestartCode, Scale: 1.5},
+		restart,
+	)
+
+	content := HStack(30, stats, &Picture{
+		widget:  widget{Visible: func(g *Game) bool { return g.gameOverImage != nil }},
+		Image:   func(g *Game) *ebiten.Image { return g.gameOverImage },
+		MaxSize: 200,
+	})
+	content.Align = AlignCenter
+
+	return NewUI(&Panel{widget: widget{MinW: 500, MinH: 300}, Padding: 40, Child: content})
+}
+
+// restartCodeChar accepts the letters of the restart code, lowercased
+func restartCodeChar(r rune) (rune, bool) {
+	r = unicode.ToLower(r)
+	return r, r >= 'a' && r <= 'z'
+}
+
+// submitRestartCode restarts on the code "anay" and clears a wrong one
+func submitRestartCode(g *Game, in *TextInput) {
+	if in.Value == "anay" {
+		g.restart()
+		return
+	}
+	in.Value = ""
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Settings ---
+
+// settingsVersion is bumped whenever the meaning or default of a saved value
+// changes, so older files can be migrated on load. Adding a field alone needs
+// no bump: files are decoded over defaultSettings, so a field missing from an
+// older file keeps its default. Fields added without a bump say so below.
+const settingsVersion = 6
+
+// Settings holds the player's persisted preferences
+type Settings struct {
+	Version   int                     `json:"version"`   // Format version of the file
+	AssetPack string                  `json:"assetPack"` // ID of the active asset pack (directory name under packs/)
+	Bindings  map[string][]ebiten.Key `json:"bindings"`  // Keys bound to each action, by action name (added in version 2)
+
+	// Input, timing, collision and audio (added in version 2, without a bump;
+	// version 2 files written before them get the defaults)
+	PointerSteering bool `json:"pointerSteering"` // Leader follows the mouse cursor / touch point instead of the movement keys
+	TPS             int  `json:"tps"`             // Update rate (one of supportedTPS); the simulation itself always runs at SimTPS
+
+	CollisionMode CollisionMode `json:"collisionMode"` // Shape the school collides with kelp as
+
+	MasterVolume float64 `json:"masterVolume"` // 0 to 1, scales both music and sound effects
+	MusicVolume  float64 `json:"musicVolume"`  // 0 to 1
+	SFXVolume    float64 `json:"sfxVolume"`    // 0 to 1
+
+	// Display and accessibility (added in version 3)
+	Fullscreen     bool    `json:"fullscreen"`
+	WindowScale    float64 `json:"windowScale"`    // Window size as a multiple of ScreenWidth x ScreenHeight (one of windowScales)
+	VSync          bool    `json:"vsync"`          // Wait for the display's refresh before presenting frames
+	ReducedEffects bool    `json:"reducedEffects"` // Hide purely decorative motion (bubbles, wake, background fish)
+	Language       string  `json:"language"`       // Language code (one of the locale files, see listLanguages)
+
+	ScaleMode ScaleMode `json:"scaleMode"` // How the game is scaled to the window (added in version 4)
+
+	// Accessibility (added in version 5)
+	Palette        Palette `json:"palette"`        // Color scheme applied on top of the asset pack's colors
+	HighContrast   bool    `json:"highContrast"`   // Dark scenery and outlined hazards and coins
+	CollisionZones bool    `json:"collisionZones"` // Shade hazards' collision areas and outline the school's hitboxes
+
+	// Added in version 6
+	ReducedMotion bool           `json:"reducedMotion"` // No kelp waving, bubble wobble, wake, camera motion or background fish
+	Assist        AssistSettings `json:"assist"`
+}
+
+// AssistSettings make runs easier. Runs played with assist mode on are ranked
+// separately on the leaderboard.
+type AssistSettings struct {
+	Enabled    bool    `json:"enabled"`
+	SpeedCap   float64 `json:"speedCap"`   // Highest speed multiplier a run reaches (one of assistSpeedCaps)
+	GapScale   float64 `json:"gapScale"`   // Kelp gaps are this much taller (one of assistGapScales)
+	GameSpeed  float64 `json:"gameSpeed"`  // Simulation speed relative to real time (one of assistGameSpeeds)
+	Invincible bool    `json:"invincible"` // Hazards don't end the run
+}
+
+// Values the assist settings can take
+var (
+	assistSpeedCaps  = []float64{2.5, 3.0, 3.5, 4.0, 4.5, MaxSpeedMultiplier}
+	assistGapScales  = []float64{1.0, 1.15, 1.3, 1.45}
+	assistGameSpeeds = []float64{0.5, 0.6, 0.7, 0.8, 0.9, 1.0}
+)
+
+// defaultAssistSettings change nothing until individual assists are picked
+func defaultAssistSettings() AssistSettings {
+	return AssistSettings{SpeedCap: MaxSpeedMultiplier, GapScale: 1.0, GameSpeed: 1.0}
+}
+
+// active returns the assist settings in effect: the defaults when assist mode is off
+func (a AssistSettings) active() AssistSettings {
+	if !a.Enabled {
+		return defaultAssistSettings()
+	}
+	return a
+}
+
+// windowScales are the window sizes the settings screen cycles through
+var windowScales = []float64{0.5, 0.75, 1.0, 1.25, 1.5, 2.0}
+
+// defaultSettings returns the settings used when no settings file exists
+func defaultSettings() *Settings {
+	return &Settings{
+		Version:       settingsVersion,
+		AssetPack:     defaultAssetPack,
+		Bindings:      defaultBindings(),
+		TPS:           SimTPS,
+		CollisionMode: CollisionCircle,
+		MasterVolume:  1.0,
+		MusicVolume:   0.6,
+		SFXVolume:     0.8,
+		WindowScale:   1.0,
+		VSync:         true,
+		Language:      fallbackLanguage,
+		ScaleMode:     ScaleFit,
+		Palette:       PaletteStandard,
+		Assist:        defaultAssistSettings(),
+	}
+}
+
+// settingsPath returns the location of the settings file in the user config directory
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "migratory-path", "settings.json"), nil
+}
+
+// LoadSettings reads the settings file, falling back to defaults for a
+// missing or unreadable file so that bad settings never stop the game
+func LoadSettings() *Settings {
+	s := defaultSettings()
+
+	path, err := settingsPath()
+	if err != nil {
+		log.Printf("Settings unavailable, using defaults: %v", err)
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to read settings, using defaults: %v", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Printf("Failed to parse settings, using defaults: %v", err)
+		return defaultSettings()
+	}
+	s.migrate()
+	return s
+}
+
+// migrate upgrades settings loaded from an older file version
+func (s *Settings) migrate() {
+	if s.AssetPack == "" {
+		s.AssetPack = defaultAssetPack
+	}
+	if s.Version < 2 || s.Bindings == nil {
+		// Version 1 had no rebindable controls
+		s.Bindings = defaultBindings()
+	}
+	if !validTPS(s.TPS) {
+		s.TPS = SimTPS
+	}
+	if s.CollisionMode != CollisionCircle && s.CollisionMode != CollisionMask {
+		s.CollisionMode = CollisionCircle
+	}
+	s.MasterVolume = clamp01(s.MasterVolume)
+	s.MusicVolume = clamp01(s.MusicVolume)
+	s.SFXVolume = clamp01(s.SFXVolume)
+	if indexOf(windowScales, s.WindowScale) < 0 {
+		s.WindowScale = 1.0
+	}
+	if languageName(s.Language) == "" {
+		s.Language = fallbackLanguage
+	}
+	if s.ScaleMode != ScaleFit && s.ScaleMode != ScaleInteger {
+		s.ScaleMode = ScaleFit
+	}
+	if indexOf(palettes, s.Palette) < 0 {
+		s.Palette = PaletteStandard
+	}
+	defaults := defaultAssistSettings()
+	if indexOf(assistSpeedCaps, s.Assist.SpeedCap) < 0 {
+		s.Assist.SpeedCap = defaults.SpeedCap
+	}
+	if indexOf(assistGapScales, s.Assist.GapScale) < 0 {
+		s.Assist.GapScale = defaults.GapScale
+	}
+	if indexOf(assistGameSpeeds, s.Assist.GameSpeed) < 0 {
+		s.Assist.GameSpeed = defaults.GameSpeed
+	}
+	s.Version = settingsVersion
+}
+
+// apply pushes the display settings to the window. The window can also be
+// resized freely; the game scales to whatever size it ends up.
+func (s *Settings) apply() {
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetTPS(s.TPS)
+	ebiten.SetVsyncEnabled(s.VSync)
+	ebiten.SetFullscreen(s.Fullscreen)
+}
+
+// resizeWindow sets the window to the size chosen by WindowScale
+func (s *Settings) resizeWindow() {
+	ebiten.SetWindowSize(int(ScreenWidth*s.WindowScale), int(ScreenHeight*s.WindowScale))
+}
+
+// indexOf returns the position of v in values, or -1
+func indexOf[T comparable](values []T, v T) int {
+	for i, x := range values {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Save writes the settings file, creating the config directory if needed
+func (s *Settings) Save() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	return nil
+}
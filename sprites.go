@@ -3,10 +3,11 @@ package main
 import (
 	"image"
 	"image/color"
+	"log"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 // --- Sprite Creation Functions ---
@@ -16,75 +17,62 @@ func setPixel(img *image.RGBA, x, y int, c color.Color) {
 	img.Set(x, y, c)
 }
 
-// loadImageFromFile loads an image from a file path
-func loadImageFromFile(path string) (*ebiten.Image, error) {
-	img, _, err := ebitenutil.NewImageFromFile(path)
+// createFishImage loads a fish sprite asset, falling back to a generated fish if
+// the asset is missing or can't be decoded
+func createFishImage(name string) image.Image {
+	img, err := loadRawImageAsset(name)
 	if err != nil {
-		return nil, err
-	}
-	return img, nil
-}
-
-// createFishSprite loads the fish sprite from a PNG file
-func createFishSprite() *ebiten.Image {
-	img, err := loadImageFromFile("assets/fish.png")
-	if err != nil {
-		panic("Failed to load fish.png: " + err.Error())
+		log.Printf("Failed to load %s, using fallback sprite: %v", name, err)
+		return createFallbackFishImage()
 	}
 	return img
 }
 
-// createKelpSprite creates a pixel art kelp sprite (vertical strip)
-func createKelpSprite() *ebiten.Image {
-	// Create a 8x32 pixel art kelp strip (will be tiled/scaled)
-	width := 8
-	height := 32
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
-	
-	// Define colors
+// drawKelpStrip draws one pixel art kelp frame (vertical strip) into img at originX.
+// The phase shifts the wave pattern so consecutive frames animate the stem.
+func drawKelpStrip(img *image.RGBA, originX, width, height int, phase float64, colors *PackColors) {
+	// Define colors (from the active asset pack)
 	transparent := color.RGBA{0, 0, 0, 0}
-	kelpDark := color.RGBA{0, 80, 0, 255}      // Dark green
-	kelpMedium := color.RGBA{0, 120, 0, 255}   // Medium green
-	kelpLight := color.RGBA{0, 160, 0, 255}    // Light green
-	kelpAccent := color.RGBA{20, 100, 20, 255} // Accent green
+	kelpDark := colors.KelpDark
+	kelpMedium := colors.KelpMedium
+	kelpLight := colors.KelpLight
+	kelpAccent := colors.KelpAccent
 	
 	// Create a wavy kelp pattern (vertical)
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			// Create a wavy pattern
-			waveOffset := int(math.Sin(float64(y)*0.3) * 1.5)
+			waveOffset := int(math.Sin(float64(y)*0.3+phase) * 1.5)
 			centerX := width/2 + waveOffset
 			
 			if x == centerX || x == centerX-1 || x == centerX+1 {
 				// Main stem - darker in center
 				if x == centerX {
-					setPixel(img, x, y, kelpDark)
+					setPixel(img, originX+x, y, kelpDark)
 				} else {
-					setPixel(img, x, y, kelpMedium)
+					setPixel(img, originX+x, y, kelpMedium)
 				}
 			} else if x == centerX-2 || x == centerX+2 {
 				// Outer edge - lighter
-				setPixel(img, x, y, kelpLight)
+				setPixel(img, originX+x, y, kelpLight)
 			} else {
-				setPixel(img, x, y, transparent)
+				setPixel(img, originX+x, y, transparent)
 			}
 			
 			// Add some texture variation
 			if (x+y)%3 == 0 && (x == centerX-1 || x == centerX || x == centerX+1) {
-				setPixel(img, x, y, kelpAccent)
+				setPixel(img, originX+x, y, kelpAccent)
 			}
 		}
 	}
-	
-	return ebiten.NewImageFromImage(img)
 }
 
-// drawFish draws a fish sprite at the given position
-func (g *Game) drawFish(screen *ebiten.Image, x, y, size float64, isLeader bool) {
+// drawFish draws a fish animation frame at the given position
+func (g *Game) drawFish(screen *ebiten.Image, frame *ebiten.Image, x, y, size float64, isLeader bool) {
 	op := &ebiten.DrawImageOptions{}
 	
 	// Get the sprite dimensions for proper scaling
-	spriteW, _ := g.fishSprite.Size()
+	spriteW := frame.Bounds().Dx()
 	// Scale the sprite to the desired size (assuming square sprites)
 	scale := size / float64(spriteW)
 	op.GeoM.Scale(scale, scale)
@@ -101,107 +89,156 @@ func (g *Game) drawFish(screen *ebiten.Image, x, y, size float64, isLeader bool)
 		op.ColorM.Scale(0.9, 1.0, 1.1, 1.0)
 	}
 	
-	screen.DrawImage(g.fishSprite, op)
+	screen.DrawImage(frame, op)
+	
+	// Accessibility palettes and high contrast also ring the leader, since a tint alone is easy to miss
+	if isLeader && g.settings.showLeaderMarker() {
+		vector.StrokeCircle(screen, float32(x+size/2), float32(y+size/2), float32(size*0.55), hazardOutlineWidth, g.colors.LeaderMarker, true)
+	}
 }
 
 // drawBackgroundFish draws a background fish with depth-based transparency and blur effect
 func (g *Game) drawBackgroundFish(screen *ebiten.Image, bgFish *BackgroundFish) {
 	op := &ebiten.DrawImageOptions{}
+	frame := bgFish.anim.Frame()
 	
 	// Get the sprite dimensions for proper scaling
-	spriteW, _ := g.fishSprite.Size()
+	spriteW := frame.Bounds().Dx()
 	scale := bgFish.size / float64(spriteW)
+	x, y := g.interpolate(bgFish.prevX, bgFish.x), g.interpolate(bgFish.prevY, bgFish.y)
 	
 	// Flip horizontally if moving left
 	if bgFish.direction < 0 {
 		op.GeoM.Scale(-scale, scale)
-		op.GeoM.Translate(bgFish.x+bgFish.size, bgFish.y)
+		op.GeoM.Translate(x+bgFish.size, y)
 	} else {
 		op.GeoM.Scale(scale, scale)
-		op.GeoM.Translate(bgFish.x, bgFish.y)
+		op.GeoM.Translate(x, y)
 	}
 	
 	// Apply depth-based transparency and color (more transparent = further back)
 	alpha := bgFish.depth * 0.4 // 0.12 to 0.28 alpha (very transparent)
 	
+	// High contrast fades them further so they can't be mistaken for the school
+	if g.settings.HighContrast {
+		alpha *= 0.5
+	}
+	
 	// Lighter/more washed out color for background fish
 	op.ColorM.Scale(0.7+bgFish.depth*0.3, 0.8+bgFish.depth*0.2, 1.0, alpha)
 	
-	screen.DrawImage(g.fishSprite, op)
+	screen.DrawImage(frame, op)
 }
 
-// drawBubble draws a bubble with transparency
-func (g *Game) drawBubble(screen *ebiten.Image, bubble *Bubble) {
-	// Draw bubble as a circle with transparency
-	// We'll draw two circles - outer (lighter) and inner (highlight)
-	
-	outerColor := color.RGBA{200, 230, 255, 80} // Light blue, semi-transparent
-	innerColor := color.RGBA{255, 255, 255, 120} // White highlight, more opaque
-	
-	// Draw outer circle (main bubble)
-	drawCircle(screen, bubble.x, bubble.y, bubble.size, outerColor)
+// drawCoin draws the current frame of a spinning coin
+func (g *Game) drawCoin(screen *ebiten.Image, coin *Coin) {
+	sprite := coin.anim.Frame()
+	op := &ebiten.DrawImageOptions{}
+	x := g.interpolate(coin.prevX, coin.x)
+	op.GeoM.Translate(x, coin.y)
+	screen.DrawImage(sprite, op)
 	
-	// Draw inner highlight (smaller, offset up and left)
-	highlightSize := bubble.size * 0.4
-	highlightX := bubble.x - bubble.size*0.25
-	highlightY := bubble.y - bubble.size*0.25
-	drawCircle(screen, highlightX, highlightY, highlightSize, innerColor)
+	// Outline in high contrast mode
+	if g.settings.HighContrast {
+		r := coin.size / 2
+		vector.StrokeCircle(screen, float32(x+r), float32(coin.y+r), float32(r+2), 2, g.colors.Outline, true)
+	}
 }
 
-// drawCoin draws a circular coin with a shiny appearance
-func (g *Game) drawCoin(screen *ebiten.Image, coin *Coin) {
-	radius := coin.size / 2
-	centerX := coin.x + radius
-	centerY := coin.y + radius
+// coinCacheKey identifies a pre-rendered coin by pixel size and pack palette
+type coinCacheKey struct {
+	size   int
+	colors PackColors
+}
+
+// coinSpriteCache holds pre-rendered coins
+var coinSpriteCache = map[coinCacheKey]*ebiten.Image{}
+
+// coinSprite returns the pre-rendered coin face for the given size and palette,
+// rendering the layered circles once the first time they are requested
+func coinSprite(size float64, colors *PackColors) *ebiten.Image {
+	key := coinCacheKey{size: int(math.Ceil(size)), colors: *colors}
+	if sprite, ok := coinSpriteCache[key]; ok {
+		return sprite
+	}
+	
+	sprite := ebiten.NewImage(key.size, key.size)
+	radius := size / 2
+	centerX := radius
+	centerY := radius
 	
 	// Draw outer dark border
-	borderColor := color.RGBA{180, 140, 0, 255} // Dark gold
-	drawCircle(screen, centerX, centerY, radius, borderColor)
+	borderColor := colors.CoinBorder // Dark gold
+	drawCircle(sprite, centerX, centerY, radius, borderColor)
 	
 	// Draw main coin body (slightly smaller)
-	mainColor := color.RGBA{255, 215, 0, 255} // Gold
-	drawCircle(screen, centerX, centerY, radius*0.9, mainColor)
+	mainColor := colors.CoinMain // Gold
+	drawCircle(sprite, centerX, centerY, radius*0.9, mainColor)
 	
 	// Draw highlight (top-left)
-	highlightColor := color.RGBA{255, 245, 150, 255} // Bright gold
+	highlightColor := colors.CoinHighlight // Bright gold
 	highlightX := centerX - radius*0.3
 	highlightY := centerY - radius*0.3
-	drawCircle(screen, highlightX, highlightY, radius*0.4, highlightColor)
+	drawCircle(sprite, highlightX, highlightY, radius*0.4, highlightColor)
 	
 	// Draw shadow/darker area (bottom-right) for depth
-	shadowColor := color.RGBA{200, 170, 0, 255} // Darker gold
+	shadowColor := colors.CoinShadow // Darker gold
 	shadowX := centerX + radius*0.2
 	shadowY := centerY + radius*0.2
-	drawCircle(screen, shadowX, shadowY, radius*0.5, shadowColor)
+	drawCircle(sprite, shadowX, shadowY, radius*0.5, shadowColor)
+	
+	coinSpriteCache[key] = sprite
+	return sprite
 }
 
-// Helper function to draw a filled circle
-func drawCircle(screen *ebiten.Image, cx, cy, radius float64, col color.Color) {
-	// Create a small image for the circle and draw it
-	size := int(radius * 2)
-	if size < 1 {
-		size = 1
-	}
+// circleSpriteRadius is the radius the shared circle sprite is rendered at.
+// Every circle is drawn by scaling and tinting this one image, which lets
+// ebiten batch them into a handful of draw calls.
+const circleSpriteRadius = 64
+
+// circleSprite is the cached white circle used by drawCircle
+var circleSprite *ebiten.Image
+
+// createCircleSprite creates a white anti-aliased filled circle
+func createCircleSprite() *ebiten.Image {
+	size := circleSpriteRadius * 2
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
 	
-	// Draw circle by checking distance from center
 	for y := 0; y < size; y++ {
 		for x := 0; x < size; x++ {
-			dx := float64(x) - radius
-			dy := float64(y) - radius
-			if dx*dx+dy*dy <= radius*radius {
-				px := int(cx - radius + float64(x))
-				py := int(cy - radius + float64(y))
-				if px >= 0 && px < ScreenWidth && py >= 0 && py < ScreenHeight {
-					screen.Set(px, py, col)
-				}
-			}
+			// Distance from the pixel center to the circle center
+			dx := float64(x) + 0.5 - circleSpriteRadius
+			dy := float64(y) + 0.5 - circleSpriteRadius
+			distance := math.Sqrt(dx*dx + dy*dy)
+			
+			// Fade the outermost pixel for a smooth edge
+			coverage := math.Max(0, math.Min(1, circleSpriteRadius-distance+0.5))
+			a := uint8(coverage * 255)
+			setPixel(img, x, y, color.RGBA{a, a, a, a})
 		}
 	}
+	
+	return ebiten.NewImageFromImage(img)
 }
 
-// drawKelp draws kelp by tiling the kelp sprite vertically with wave animation
-func (g *Game) drawKelp(screen *ebiten.Image, x, y, width, height float64) {
-	kelpTileHeight := 32.0 // Height of one kelp tile
+// Helper function to draw a filled circle
+func drawCircle(screen *ebiten.Image, cx, cy, radius float64, col color.Color) {
+	if circleSprite == nil {
+		circleSprite = createCircleSprite()
+	}
+	
+	op := &ebiten.DrawImageOptions{}
+	scale := radius / circleSpriteRadius
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(cx-radius, cy-radius)
+	op.ColorScale.ScaleWithColor(col)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(circleSprite, op)
+}
+
+// drawKelp draws kelp by tiling the current kelp animation frame vertically with wave animation
+func (g *Game) drawKelp(screen *ebiten.Image, frame *ebiten.Image, x, y, width, height float64) {
+	kelpTileHeight := float64(frame.Bounds().Dy()) // Height of one kelp tile
 	tiles := int(height / kelpTileHeight) + 1
 	
 	for i := 0; i < tiles; i++ {
@@ -217,20 +254,91 @@ func (g *Game) drawKelp(screen *ebiten.Image, x, y, width, height float64) {
 		positionOffset := x * 0.01 // Offset based on x position for variety
 		yOffset := tileY * 0.015 // More wave at the top
 		
-		// Create a smooth wave motion using sine
+		// Create a smooth wave motion using sine (none with reduced motion)
 		waveAmplitude := 3.0 + (tileY-y)/height*8.0 // Stronger wave at top of kelp
+		if g.settings.ReducedMotion {
+			waveAmplitude = 0
+		}
 		waveX := math.Sin(timeOffset+positionOffset+yOffset) * waveAmplitude
 		
 		op := &ebiten.DrawImageOptions{}
 		// Scale to match width and tile height
-		scaleX := width / 8.0
+		scaleX := width / float64(frame.Bounds().Dx())
 		scaleY := tileHeight / kelpTileHeight
 		op.GeoM.Scale(scaleX, scaleY)
 		
 		// Apply wave offset and translate to position
 		op.GeoM.Translate(x+waveX, tileY)
 		
-		screen.DrawImage(g.kelpSprite, op)
+		screen.DrawImage(frame, op)
+	}
+	
+	// Outline the kelp's collision rectangle in high contrast mode
+	if g.settings.HighContrast {
+		vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), hazardOutlineWidth, g.colors.Outline, false)
 	}
 }
 
+// --- Jellyfish ---
+
+// jellyfishSprites are generated on first use for each palette and shared by every jellyfish
+var jellyfishSprites = map[PackColors]*ebiten.Image{}
+
+// createJellyfishSprite draws a translucent bell with wavy tentacles hanging below it
+func createJellyfishSprite(size int, colors *PackColors) *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	bellColor := colors.JellyBell // Pink, semi-transparent in the default pack
+	rimColor := colors.JellyRim   // Lighter rim
+	tentacleColor := colors.JellyTentacle
+
+	cx := float64(size) / 2
+	bellRadius := float64(size) * 0.45
+	bellBottom := int(float64(size) * 0.5)
+
+	// Bell: the top half of an ellipse
+	for y := 0; y < bellBottom; y++ {
+		for x := 0; x < size; x++ {
+			dx := (float64(x) - cx) / bellRadius
+			dy := (float64(y) - float64(bellBottom)) / (float64(bellBottom) * 0.95)
+			d := dx*dx + dy*dy
+			if d <= 1 {
+				if d > 0.75 || y >= bellBottom-2 {
+					setPixel(img, x, y, rimColor)
+				} else {
+					setPixel(img, x, y, bellColor)
+				}
+			}
+		}
+	}
+
+	// Tentacles: thin sine curves below the bell
+	for i := 0; i < 5; i++ {
+		baseX := cx - bellRadius*0.7 + float64(i)*bellRadius*0.35
+		for y := bellBottom; y < size; y++ {
+			sway := math.Sin(float64(y)*0.3+float64(i)) * 2
+			setPixel(img, int(baseX+sway), y, tentacleColor)
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// drawJellyfish draws a jellyfish at its interpolated position
+func (g *Game) drawJellyfish(screen *ebiten.Image, jelly *Jellyfish) {
+	sprite, ok := jellyfishSprites[g.colors]
+	if !ok {
+		sprite = createJellyfishSprite(JellyfishSize, &g.colors)
+		jellyfishSprites[g.colors] = sprite
+	}
+	x, y := g.interpolate(jelly.prevX, jelly.x), g.interpolate(jelly.prevY, jelly.y)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(jelly.size/JellyfishSize, jelly.size/JellyfishSize)
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(sprite, op)
+
+	// Outline its collision circle in high contrast mode
+	if g.settings.HighContrast {
+		c := jelly.collisionCircle()
+		vector.StrokeCircle(screen, float32(c.x+x-jelly.x), float32(c.y+y-jelly.y), float32(c.radius), hazardOutlineWidth, g.colors.Outline, true)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"image/color"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Test Setup ---
+
+// benchGame keeps Ebitengine's game loop running while the tests run on
+// another goroutine. Draw commands are only queued outside the loop;
+// benchmarks that draw read a pixel back each iteration, which makes the
+// loop flush the queued commands to the GPU and wait for them.
+type benchGame struct {
+	done chan struct{}
+}
+
+func (g *benchGame) Update() error {
+	select {
+	case <-g.done:
+		return ebiten.Termination
+	default:
+		return nil
+	}
+}
+
+func (g *benchGame) Draw(screen *ebiten.Image) {}
+
+func (g *benchGame) Layout(w, h int) (int, int) { return ScreenWidth, ScreenHeight }
+
+// TestMain runs benchmarks in the game loop, which needs a window. Plain
+// test runs don't draw anything and skip it, so they work without a display.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if f := flag.Lookup("test.bench"); f == nil || f.Value.String() == "" {
+		os.Exit(m.Run())
+	}
+
+	code := 0
+	g := &benchGame{done: make(chan struct{})}
+	go func() {
+		code = m.Run()
+		close(g.done)
+	}()
+	ebiten.SetWindowSize(320, 180)
+	if err := ebiten.RunGame(g); err != nil {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+// --- Circle Benchmarks ---
+
+// drawCirclePerPixel is how circles were drawn before the cached sprite:
+// one Set call per covered pixel
+func drawCirclePerPixel(screen *ebiten.Image, cx, cy, radius float64, col color.Color) {
+	size := int(radius * 2)
+	if size < 1 {
+		size = 1
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := float64(x) - radius
+			dy := float64(y) - radius
+			if dx*dx+dy*dy <= radius*radius {
+				px := int(cx - radius + float64(x))
+				py := int(cy - radius + float64(y))
+				if px >= 0 && px < ScreenWidth && py >= 0 && py < ScreenHeight {
+					screen.Set(px, py, col)
+				}
+			}
+		}
+	}
+}
+
+// benchmarkBubbles measures one frame's worth of bubbles: NumBubbles
+// bubbles, each an outer circle and a highlight, drawn with draw. Reading a
+// pixel back ends each iteration, so the time includes the GPU work and not
+// just queueing the commands.
+func benchmarkBubbles(b *testing.B, draw func(screen *ebiten.Image, cx, cy, radius float64, col color.Color)) {
+	rng := rand.New(rand.NewSource(1))
+	type bubble struct{ x, y, size float64 }
+	bubbles := make([]bubble, NumBubbles)
+	for i := range bubbles {
+		bubbles[i] = bubble{rng.Float64() * ScreenWidth, rng.Float64() * ScreenHeight, 3 + rng.Float64()*8}
+	}
+	colors := defaultPackColors()
+	screen := ebiten.NewImage(ScreenWidth, ScreenHeight)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, bb := range bubbles {
+			draw(screen, bb.x, bb.y, bb.size, colors.BubbleOuter)
+			draw(screen, bb.x-bb.size*0.25, bb.y-bb.size*0.25, bb.size*0.4, colors.BubbleInner)
+		}
+		screen.At(0, 0)
+	}
+}
+
+func BenchmarkBubblesPerPixelSet(b *testing.B) {
+	benchmarkBubbles(b, drawCirclePerPixel)
+}
+
+func BenchmarkBubblesCachedSprite(b *testing.B) {
+	benchmarkBubbles(b, drawCircle)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// --- Sound Synthesis ---
+
+// The built-in sounds are generated in code, the same way the sprites are.
+// Everything here is plain arithmetic on a fixed seed, so the same build
+// always produces the same PCM.
+
+// waveform is the shape of an oscillator
+type waveform int
+
+const (
+	waveSine waveform = iota
+	waveSquare
+	waveTriangle
+	waveSaw
+	waveNoise
+)
+
+// envelope is an ADSR amplitude envelope; times are in seconds and sustain is a level (0 to 1)
+type envelope struct {
+	attack, decay, sustain, release float64
+}
+
+// level returns the envelope's amplitude t seconds into a note held for hold seconds
+func (e envelope) level(t, hold float64) float64 {
+	switch {
+	case t < 0:
+		return 0
+	case t < e.attack:
+		return t / e.attack
+	case t < e.attack+e.decay:
+		return 1 - (1-e.sustain)*(t-e.attack)/e.decay
+	case t < hold:
+		return e.sustain
+	case t < hold+e.release:
+		return e.sustain * (1 - (t-hold)/e.release)
+	}
+	return 0
+}
+
+// noiseSource is a linear congruential generator producing white noise
+type noiseSource struct {
+	state uint32
+}
+
+// next returns the next noise sample (-1 to 1)
+func (n *noiseSource) next() float64 {
+	n.state = n.state*1664525 + 1013904223
+	return float64(n.state)/float64(math.MaxUint32)*2 - 1
+}
+
+// onePoleFilter is a one-pole low-pass filter
+type onePoleFilter struct {
+	a, y float64
+}
+
+// newLowPass returns a low-pass filter with the given cutoff frequency in Hz
+func newLowPass(cutoff float64) *onePoleFilter {
+	return &onePoleFilter{a: 1 - math.Exp(-2*math.Pi*cutoff/audioSampleRate)}
+}
+
+// process filters one sample
+func (f *onePoleFilter) process(x float64) float64 {
+	f.y += f.a * (x - f.y)
+	return f.y
+}
+
+// voice is one note: an oscillator swept from freq to freqEnd, shaped by an
+// envelope and optionally low-pass filtered
+type voice struct {
+	wave          waveform
+	freq, freqEnd float64 // Hz at the start and end of the note (equal for a steady pitch)
+	start, hold   float64 // Seconds from the start of the buffer, and how long the note is held before release
+	gain          float64
+	env           envelope
+	cutoff        float64 // Low-pass cutoff in Hz (0 = unfiltered)
+}
+
+// oscillate returns a waveform's value at phase (in cycles)
+func oscillate(wave waveform, phase float64, noise *noiseSource) float64 {
+	p := phase - math.Floor(phase)
+	switch wave {
+	case waveSquare:
+		if p < 0.5 {
+			return 1
+		}
+		return -1
+	case waveTriangle:
+		return 1 - 4*math.Abs(p-0.5)
+	case waveSaw:
+		return 2*p - 1
+	case waveNoise:
+		return noise.next()
+	}
+	return math.Sin(2 * math.Pi * p)
+}
+
+// renderVoices mixes the voices into a mono buffer of the given length in seconds
+func renderVoices(duration float64, voices []voice) []float64 {
+	out := make([]float64, int(math.Round(duration*audioSampleRate)))
+	noise := &noiseSource{state: 0x5eed}
+	for _, v := range voices {
+		var filter *onePoleFilter
+		if v.cutoff > 0 {
+			filter = newLowPass(v.cutoff)
+		}
+		first := int(math.Round(v.start * audioSampleRate))
+		// A note shorter than its attack and decay still plays them out
+		length := int((math.Max(v.hold, v.env.attack+v.env.decay) + v.env.release) * audioSampleRate)
+		phase := 0.0
+		for i := 0; i < length && first+i < len(out); i++ {
+			t := float64(i) / audioSampleRate
+			progress := float64(i) / float64(length)
+			sample := oscillate(v.wave, phase, noise) * v.env.level(t, v.hold) * v.gain
+			if filter != nil {
+				sample = filter.process(sample)
+			}
+			out[first+i] += sample
+			phase += (v.freq + (v.freqEnd-v.freq)*progress) / audioSampleRate
+		}
+	}
+	return out
+}
+
+// pcm16 converts mono samples to the 16-bit little-endian stereo PCM the audio context plays
+func pcm16(samples []float64) []byte {
+	buf := make([]byte, len(samples)*4)
+	for i, s := range samples {
+		s = math.Max(-1, math.Min(1, s))
+		v := uint16(int16(s * math.MaxInt16))
+		binary.LittleEndian.PutUint16(buf[i*4:], v)
+		binary.LittleEndian.PutUint16(buf[i*4+2:], v)
+	}
+	return buf
+}
+
+// noteFreq returns the frequency of a MIDI note number (69 = A4 = 440 Hz)
+func noteFreq(note int) float64 {
+	return 440 * math.Pow(2, float64(note-69)/12)
+}
+
+// steady returns a voice with a constant pitch
+func steady(wave waveform, note int, start, hold, gain float64, env envelope, cutoff float64) voice {
+	f := noteFreq(note)
+	return voice{wave: wave, freq: f, freqEnd: f, start: start, hold: hold, gain: gain, env: env, cutoff: cutoff}
+}
+
+// --- Sound Effects ---
+
+// synthCoinChime is two bright bell-like notes a fifth apart
+func synthCoinChime() []byte {
+	bell := envelope{attack: 0.002, decay: 0.3}
+	return pcm16(renderVoices(0.4, []voice{
+		steady(waveSine, 88, 0, 0, 0.35, bell, 0),    // E6
+		steady(waveSine, 95, 0.07, 0, 0.35, bell, 0), // B6
+		steady(waveTriangle, 100, 0.07, 0, 0.1, bell, 0),
+	}))
+}
+
+// synthBubble is a quick rising blip, played when kelp is passed
+func synthBubble() []byte {
+	return pcm16(renderVoices(0.15, []voice{
+		{wave: waveSine, freq: 300, freqEnd: 900, hold: 0, gain: 0.45, env: envelope{attack: 0.005, decay: 0.12}},
+	}))
+}
+
+// synthImpactThud is a falling low tone with a burst of muffled noise
+func synthImpactThud() []byte {
+	return pcm16(renderVoices(0.5, []voice{
+		{wave: waveSine, freq: 120, freqEnd: 40, hold: 0, gain: 0.9, env: envelope{attack: 0.002, decay: 0.45}},
+		{wave: waveNoise, hold: 0, gain: 0.6, env: envelope{attack: 0.001, decay: 0.2}, cutoff: 400},
+	}))
+}
+
+// synthMenuClick is a short soft tick
+func synthMenuClick() []byte {
+	return pcm16(renderVoices(0.06, []voice{
+		steady(waveSquare, 81, 0, 0, 0.15, envelope{attack: 0.001, decay: 0.05}, 3000), // A5
+	}))
+}
+
+// synthesizedSounds are used for any sound effect without an audio file
+var synthesizedSounds = [numSounds]func() []byte{
+	SoundCoin:      synthCoinChime,
+	SoundPass:      synthBubble,
+	SoundCollision: synthImpactThud,
+	SoundMenu:      synthMenuClick,
+}
+
+// --- Music ---
+
+// The music is a four-bar loop over Am - F - C - G. Every layer has exactly
+// the same length so they stay in sync when looped.
+const (
+	musicBeat  = 0.6 // Seconds per beat (100 BPM)
+	musicBars  = 4
+	musicBeats = musicBars * 4
+	musicLoop  = musicBeats * musicBeat
+)
+
+// musicChords are the MIDI notes of each bar's triad
+var musicChords = [musicBars][3]int{
+	{57, 60, 64}, // Am
+	{53, 57, 60}, // F
+	{48, 52, 55}, // C
+	{55, 59, 62}, // G
+}
+
+// synthMusicBase is a soft sustained bass and chord pad
+func synthMusicBase() []byte {
+	pad := envelope{attack: 0.3, decay: 0.5, sustain: 0.7, release: 0.3}
+	var voices []voice
+	for bar, chord := range musicChords {
+		start := float64(bar*4) * musicBeat
+		hold := 4*musicBeat - pad.release
+		voices = append(voices, steady(waveTriangle, chord[0]-12, start, hold, 0.3, pad, 600))
+		for _, note := range chord {
+			voices = append(voices, steady(waveSine, note, start, hold, 0.08, pad, 0))
+		}
+	}
+	return pcm16(renderVoices(musicLoop, voices))
+}
+
+// synthMusicDrums is a kick on beats 1 and 3, a snare on 2 and 4 and hi-hats on every eighth
+func synthMusicDrums() []byte {
+	var voices []voice
+	for beat := 0; beat < musicBeats; beat++ {
+		start := float64(beat) * musicBeat
+		if beat%2 == 0 {
+			voices = append(voices, voice{wave: waveSine, freq: 150, freqEnd: 50, start: start, gain: 0.7, env: envelope{attack: 0.002, decay: 0.15}})
+		} else {
+			voices = append(voices,
+				voice{wave: waveNoise, start: start, gain: 0.3, env: envelope{attack: 0.001, decay: 0.12}, cutoff: 2500},
+				voice{wave: waveTriangle, freq: 200, freqEnd: 180, start: start, gain: 0.2, env: envelope{attack: 0.001, decay: 0.08}},
+			)
+		}
+		for eighth := 0; eighth < 2; eighth++ {
+			voices = append(voices, voice{wave: waveNoise, start: start + float64(eighth)*musicBeat/2, gain: 0.08, env: envelope{attack: 0.001, decay: 0.03}})
+		}
+	}
+	return pcm16(renderVoices(musicLoop, voices))
+}
+
+// synthMusicLead is an arpeggio of each bar's chord, an octave up, in eighth notes
+func synthMusicLead() []byte {
+	pluck := envelope{attack: 0.005, decay: 0.2, sustain: 0.3, release: 0.05}
+	pattern := []int{0, 1, 2, 1, 0, 2, 1, 2} // Chord tone per eighth note
+	var voices []voice
+	for bar, chord := range musicChords {
+		for i, tone := range pattern {
+			start := float64(bar*4)*musicBeat + float64(i)*musicBeat/2
+			voices = append(voices, steady(waveSquare, chord[tone]+12, start, musicBeat/2-pluck.release, 0.18, pluck, 1800))
+		}
+	}
+	return pcm16(renderVoices(musicLoop, voices))
+}
+
+// synthesizedMusic are used for any music layer (by index in musicLayerFiles) without an audio file
+var synthesizedMusic = []func() []byte{
+	synthMusicBase,
+	synthMusicDrums,
+	synthMusicLead,
+}
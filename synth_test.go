@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"runtime"
+	"testing"
+)
+
+// --- Synthesis ---
+
+// synthGoldens are the SHA-256 hashes of every synthesized sound and music
+// layer, so a change to the synthesizer that alters the output is noticed.
+// Floating point results can differ in the last bit between architectures
+// (fused multiply-add, assembly math routines), so the hashes are only
+// compared on amd64, where they were recorded; elsewhere the test checks
+// that synthesis is deterministic. After an intended change, update the
+// hashes from the test's output.
+var synthGoldens = []struct {
+	name   string
+	synth  func() []byte
+	sha256 string
+}{
+	{"coin", synthesizedSounds[SoundCoin], "c98ad2244cfa10273a9abe2ea060294eea6e5c88292cf4d6483ddd4239aaf2ba"},
+	{"pass", synthesizedSounds[SoundPass], "7663382861ab5ce21a3b1cd86dfa5ff86dd632335f5a0c510c9ad38a49233713"},
+	{"collision", synthesizedSounds[SoundCollision], "5a651537b022d0aabee2a28bba403cf7494f70154b68ed134cadfed8b7031efc"},
+	{"menu", synthesizedSounds[SoundMenu], "f2831fe911e3209f69e7438da2ca699516ec3671bc20b4be483ee657797622b2"},
+	{"music base", synthesizedMusic[0], "bc9827f468c281a12c75f33533db72ca54c7413a756ee8b131660d9571fe8e74"},
+	{"music drums", synthesizedMusic[1], "2c2973af68ef3385aa5d25c691c4a5f63c7ab78ec7400b6be64e944a7f993c80"},
+	{"music lead", synthesizedMusic[2], "9d9b7f750ed28b3d24ef0de648690196c0fbc0b3316ee42771fc23bffb6436fb"},
+}
+
+func TestSynthesizedAudioMatchesGoldens(t *testing.T) {
+	for _, tt := range synthGoldens {
+		pcm := tt.synth()
+		if len(pcm) == 0 || len(pcm)%4 != 0 {
+			t.Errorf("%s: %d bytes of PCM, want a whole number of 16-bit stereo frames", tt.name, len(pcm))
+		}
+		if again := tt.synth(); !bytes.Equal(pcm, again) {
+			t.Errorf("%s: synthesizing twice gave different output", tt.name)
+		}
+		if runtime.GOARCH != "amd64" {
+			continue
+		}
+		sum := sha256.Sum256(pcm)
+		if got := hex.EncodeToString(sum[:]); got != tt.sha256 {
+			t.Errorf("%s: SHA-256 = %s, want %s", tt.name, got, tt.sha256)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// --- Fixed Timestep ---
+
+// The simulation always advances in steps of 1/SimTPS seconds, whatever rate
+// Ebitengine calls Update at. Movement constants are per step, so a run plays
+// out identically at every TPS setting.
+const (
+	SimTPS            = 60 // Simulation steps per second
+	maxStepsPerUpdate = 8  // Steps run in one Update before the backlog is dropped
+)
+
+// supportedTPS are the Update rates the TPS setting can choose between
+var supportedTPS = []int{60, 120, 144}
+
+// validTPS reports whether tps is one of supportedTPS
+func validTPS(tps int) bool {
+	for _, t := range supportedTPS {
+		if t == tps {
+			return true
+		}
+	}
+	return false
+}
+
+// advanceSimulation adds the time covered by this Update to the accumulator
+// and runs every whole step that fits in it. The remainder is kept as the
+// interpolation factor Draw uses between the previous and current step.
+func (g *Game) advanceSimulation() {
+	// Ebitengine calls Update TPS times per second (catching up after slow
+	// frames), so counting time in Update calls rather than reading the wall
+	// clock keeps runs reproducible on any machine
+	tps := ebiten.TPS()
+	if tps <= 0 {
+		tps = SimTPS
+	}
+	g.accumulator += float64(SimTPS) / float64(tps) * g.timeScale * g.settings.Assist.active().GameSpeed
+
+	steps := 0
+	// The epsilon absorbs rounding, e.g. 144 Updates of 60/144 summing to just under 60 steps
+	for g.accumulator >= 1-1e-9 {
+		g.savePreviousPositions()
+		if g.gameOver {
+			// Only particles and the camera's shake keep moving after a crash, with the scene frozen around them
+			g.effects.update(0, g.settings)
+			g.updateCamera()
+		} else {
+			g.step()
+		}
+		g.accumulator--
+		steps++
+		if steps == maxStepsPerUpdate {
+			// Too far behind to catch up; drop the backlog rather than spiral
+			g.accumulator = 0
+		}
+	}
+	if g.accumulator < 0 {
+		g.accumulator = 0
+	}
+}
+
+// stepAlpha is how far the current Update lies between the latest step and the next (0 to 1)
+func (g *Game) stepAlpha() float64 {
+	if g.accumulator > 1 {
+		return 1
+	}
+	return g.accumulator
+}
+
+// interpolate blends a value from before and after the latest step for drawing
+func (g *Game) interpolate(prev, cur float64) float64 {
+	return prev + (cur-prev)*g.stepAlpha()
+}
+
+// savePreviousPositions records where everything is before a step moves it
+func (g *Game) savePreviousPositions() {
+	g.prevPlayerY = g.playerY
+	for _, fish := range g.fish {
+		fish.prevX, fish.prevY = fish.x, fish.y
+	}
+	for _, obs := range g.obstacles {
+		obs.prevX = obs.x
+	}
+	for _, coin := range g.coins {
+		coin.prevX = coin.x
+	}
+	for _, jelly := range g.jellyfish {
+		jelly.prevX, jelly.prevY = jelly.x, jelly.y
+	}
+	for _, bgFish := range g.backgroundFish {
+		bgFish.prevX, bgFish.prevY = bgFish.x, bgFish.y
+	}
+	g.effects.savePrevious()
+	g.camera.prev = g.camera.pose
+	for _, layer := range g.parallaxLayers {
+		layer.prevOffset = layer.offset
+	}
+}
@@ -0,0 +1,894 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// --- UI Toolkit ---
+
+// Menus and panels are trees of widgets. Containers (panels, stacks and
+// lists) size themselves from their children and lay them out, so a screen
+// is declared rather than positioned pixel by pixel. A UI wraps a tree,
+// centers it on the screen, moves focus between its interactive widgets with
+// the keyboard, gamepad and pointer, and draws it in the active theme. Like
+// HUD elements, widgets read the game through func(g *Game) callbacks, so a
+// tree stays valid when a restart replaces the Game's contents.
+
+// --- Theme ---
+
+// Theme holds the colors and spacing shared by every screen
+type Theme struct {
+	Overlay     color.Color // Dims the game behind a screen
+	Panel       color.Color // Panel background
+	Border      color.Color // Panel border
+	BorderWidth float64
+	Text        color.Color // Default text
+	Subtle      color.Color // Hints and secondary text
+	Value       color.Color // Values of choices and sliders
+	Focus       color.Color // Background of the focused widget
+	Active      color.Color // Background of a widget taking input (e.g. waiting for a key)
+	Button      color.Color // Background of filled buttons
+	Track       color.Color // Empty part of a slider
+}
+
+var defaultTheme = &Theme{
+	Overlay:     color.RGBA{0, 0, 0, 180},
+	Panel:       color.RGBA{40, 40, 40, 255},
+	Border:      color.White,
+	BorderWidth: 3,
+	Text:        color.White,
+	Subtle:      color.RGBA{200, 200, 200, 255},
+	Value:       color.RGBA{150, 220, 255, 255},
+	Focus:       color.RGBA{70, 90, 140, 255},
+	Active:      color.RGBA{140, 100, 40, 255},
+	Button:      color.RGBA{60, 120, 60, 255},
+	Track:       color.RGBA{90, 90, 90, 255},
+}
+
+// highContrastTheme puts white and yellow on black for the high contrast setting
+var highContrastTheme = &Theme{
+	Overlay:     color.RGBA{0, 0, 0, 220},
+	Panel:       color.Black,
+	Border:      color.RGBA{255, 220, 0, 255},
+	BorderWidth: 4,
+	Text:        color.White,
+	Subtle:      color.White,
+	Value:       color.RGBA{255, 220, 0, 255},
+	Focus:       color.RGBA{0, 80, 200, 255},
+	Active:      color.RGBA{180, 90, 0, 255},
+	Button:      color.RGBA{0, 110, 0, 255},
+	Track:       color.RGBA{110, 110, 110, 255},
+}
+
+// themeFor returns the theme matching the accessibility settings
+func themeFor(s *Settings) *Theme {
+	if s.HighContrast {
+		return highContrastTheme
+	}
+	return defaultTheme
+}
+
+// --- Widgets ---
+
+// Align positions a child across a stack (horizontally in a vertical stack)
+type Align int
+
+const (
+	AlignDefault Align = iota // Use the stack's alignment
+	AlignStart
+	AlignCenter
+	AlignEnd
+	AlignStretch // Fill the stack's width (or height)
+)
+
+// Widget is a node of a UI tree
+type Widget interface {
+	base() *widget
+	measure() (w, h float64)          // Preferred size (MinW and MinH are applied on top)
+	place(x, y, w, h float64)         // Lays the widget and its children out in the given bounds
+	draw(screen *ebiten.Image, u *UI) // Draws the widget and its children
+	children() []Widget
+}
+
+// widget holds the layout options every widget has, and where it was placed
+type widget struct {
+	MinW, MinH float64            // Smallest size the widget is laid out at
+	Align      Align              // Alignment within the parent stack
+	Visible    func(g *Game) bool // The widget and its space are removed while this returns false (nil is always visible)
+
+	bounds clickRegion // Where the widget was placed, in logical pixels
+	hidden bool        // Visible returned false at the last refresh
+	shown  bool        // Laid out on screen (false when hidden or scrolled out of a list)
+}
+
+func (w *widget) base() *widget      { return w }
+func (w *widget) children() []Widget { return nil }
+func (w *widget) setBounds(x, y, width, height float64) {
+	w.bounds = clickRegion{x, y, width, height}
+	w.shown = true
+}
+
+// sizeOf returns a widget's laid-out size: nothing if hidden, at least its minimum otherwise
+func sizeOf(w Widget) (float64, float64) {
+	b := w.base()
+	if b.hidden {
+		return 0, 0
+	}
+	mw, mh := w.measure()
+	return math.Max(mw, b.MinW), math.Max(mh, b.MinH)
+}
+
+// visibleChildren returns the children that aren't hidden
+func visibleChildren(w Widget) []Widget {
+	var out []Widget
+	for _, c := range w.children() {
+		if !c.base().hidden {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// refresher is a widget whose content is read from the game; refresh reports whether its size may have changed
+type refresher interface {
+	refresh(g *Game) bool
+}
+
+// focusable is a widget that can take focus and be activated with Enter, Confirm or a click
+type focusable interface {
+	Widget
+	activate(g *Game)
+}
+
+// adjustable is a focusable whose value Left/Right step through
+type adjustable interface {
+	adjust(g *Game, dir int)
+}
+
+// pointerTarget is a focusable that uses where it was clicked
+type pointerTarget interface {
+	press(g *Game, x, y float64)
+}
+
+// --- Text ---
+
+// textSize measures a line of text at a scale
+func textSize(s string, scale float64) (float64, float64) {
+	w, _ := text.Measure(s, uiFace, 0)
+	return w * scale, lineHeight(scale)
+}
+
+// lineHeight is the height of a line of text at a scale
+func lineHeight(scale float64) float64 {
+	m := uiFace.Metrics()
+	return (m.HAscent + m.HDescent) * scale
+}
+
+// drawText draws a line of text with its top-left corner at x, y
+func drawText(screen *ebiten.Image, s string, x, y, scale float64, clr color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(clr)
+	text.Draw(screen, s, uiFace, op)
+}
+
+// fillRect fills a rectangle in a theme color
+func fillRect(screen *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	vector.FillRect(screen, float32(x), float32(y), float32(w), float32(h), clr, false)
+}
+
+// Label is a line of localized text
+type Label struct {
+	widget
+	Text   string                    // Message key; its text is the fmt format applied to Value
+	Value  func(g *Game) interface{} // nil for fixed text; must return a comparable value
+	Scale  float64                   // Text scale (the bitmap font is small)
+	Color  color.Color               // nil uses the theme's text color
+	Subtle bool                      // Use the theme's color for hints
+
+	cache textCache
+}
+
+func (l *Label) refresh(g *Game) bool {
+	return l.cache.update(l.Text, l.Value, g)
+}
+
+func (l *Label) measure() (float64, float64) {
+	return textSize(l.cache.text, l.Scale)
+}
+
+func (l *Label) place(x, y, w, h float64) {
+	l.setBounds(x, y, w, h)
+}
+
+func (l *Label) draw(screen *ebiten.Image, u *UI) {
+	clr := l.Color
+	if clr == nil {
+		clr = u.theme.Text
+		if l.Subtle {
+			clr = u.theme.Subtle
+		}
+	}
+	drawText(screen, l.cache.text, l.bounds.x, l.bounds.y, l.Scale, clr)
+}
+
+// --- Containers ---
+
+// Stack lays its children out in a column, or in a row if Horizontal
+type Stack struct {
+	widget
+	Horizontal bool
+	Spacing    float64 // Space between children
+	Align      Align   // Alignment of children across the stack (a child's own Align wins)
+	Children   []Widget
+}
+
+// VStack stacks widgets top to bottom
+func VStack(spacing float64, children ...Widget) *Stack {
+	return &Stack{Spacing: spacing, Children: children}
+}
+
+// HStack stacks widgets left to right
+func HStack(spacing float64, children ...Widget) *Stack {
+	return &Stack{Horizontal: true, Spacing: spacing, Children: children}
+}
+
+func (s *Stack) children() []Widget { return s.Children }
+
+func (s *Stack) measure() (float64, float64) {
+	var along, across float64
+	for i, c := range visibleChildren(s) {
+		w, h := sizeOf(c)
+		if s.Horizontal {
+			w, h = h, w
+		}
+		if i > 0 {
+			along += s.Spacing
+		}
+		along += h
+		across = math.Max(across, w)
+	}
+	if s.Horizontal {
+		return along, across
+	}
+	return across, along
+}
+
+func (s *Stack) place(x, y, w, h float64) {
+	s.setBounds(x, y, w, h)
+	pos := 0.0
+	for _, c := range visibleChildren(s) {
+		cw, ch := sizeOf(c)
+		align := c.base().Align
+		if align == AlignDefault {
+			align = s.Align
+		}
+		// Work in (along, across) so rows and columns share the code
+		size, cross, room := ch, cw, w
+		if s.Horizontal {
+			size, cross, room = cw, ch, h
+		}
+		offset := 0.0
+		switch align {
+		case AlignCenter:
+			offset = (room - cross) / 2
+		case AlignEnd:
+			offset = room - cross
+		case AlignStretch:
+			cross = room
+		}
+		if s.Horizontal {
+			c.place(x+pos, y+offset, size, cross)
+		} else {
+			c.place(x+offset, y+pos, cross, size)
+		}
+		pos += size + s.Spacing
+	}
+}
+
+func (s *Stack) draw(screen *ebiten.Image, u *UI) {
+	for _, c := range visibleChildren(s) {
+		c.draw(screen, u)
+	}
+}
+
+// Spacer is empty space of its minimum size
+type Spacer struct {
+	widget
+}
+
+// vgap is vertical space between the widgets of a column
+func vgap(h float64) *Spacer {
+	return &Spacer{widget{MinH: h}}
+}
+
+// hgap is horizontal space between the widgets of a row
+func hgap(w float64) *Spacer {
+	return &Spacer{widget{MinW: w}}
+}
+
+func (s *Spacer) measure() (float64, float64)      { return 0, 0 }
+func (s *Spacer) place(x, y, w, h float64)         { s.setBounds(x, y, w, h) }
+func (s *Spacer) draw(screen *ebiten.Image, u *UI) {}
+
+// Panel is a bordered box around a widget
+type Panel struct {
+	widget
+	Padding float64 // Space between the border and the content
+	Child   Widget
+}
+
+func (p *Panel) children() []Widget { return []Widget{p.Child} }
+
+func (p *Panel) measure() (float64, float64) {
+	w, h := sizeOf(p.Child)
+	return w + p.Padding*2, h + p.Padding*2
+}
+
+func (p *Panel) place(x, y, w, h float64) {
+	p.setBounds(x, y, w, h)
+	p.Child.place(x+p.Padding, y+p.Padding, w-p.Padding*2, h-p.Padding*2)
+}
+
+func (p *Panel) draw(screen *ebiten.Image, u *UI) {
+	t := u.theme
+	r := p.bounds
+	fillRect(screen, r.x, r.y, r.w, r.h, t.Panel)
+	fillRect(screen, r.x, r.y, r.w, t.BorderWidth, t.Border)
+	fillRect(screen, r.x, r.y+r.h-t.BorderWidth, r.w, t.BorderWidth, t.Border)
+	fillRect(screen, r.x, r.y, t.BorderWidth, r.h, t.Border)
+	fillRect(screen, r.x+r.w-t.BorderWidth, r.y, t.BorderWidth, r.h, t.Border)
+	p.Child.draw(screen, u)
+}
+
+// List shows a fixed number of rows from a longer list, scrolling to keep the focused row in view
+type List struct {
+	widget
+	Items   []Widget
+	Rows    int     // Rows shown at once
+	Spacing float64 // Space between rows
+
+	scroll int // First row shown
+}
+
+func (l *List) children() []Widget { return l.Items }
+
+// rowSize is the size of every row: the widest and tallest item
+func (l *List) rowSize() (float64, float64) {
+	var w, h float64
+	for _, it := range l.Items {
+		iw, ih := sizeOf(it)
+		w, h = math.Max(w, iw), math.Max(h, ih)
+	}
+	return w, h
+}
+
+func (l *List) measure() (float64, float64) {
+	w, h := l.rowSize()
+	return w, float64(l.Rows)*(h+l.Spacing) - l.Spacing
+}
+
+func (l *List) place(x, y, w, h float64) {
+	l.setBounds(x, y, w, h)
+	_, rowH := l.rowSize()
+	for i, it := range l.Items {
+		if i < l.scroll || i >= l.scroll+l.Rows {
+			it.base().shown = false
+			continue
+		}
+		it.place(x, y+float64(i-l.scroll)*(rowH+l.Spacing), w, rowH)
+	}
+}
+
+func (l *List) draw(screen *ebiten.Image, u *UI) {
+	for _, it := range l.Items {
+		if it.base().shown {
+			it.draw(screen, u)
+		}
+	}
+}
+
+// reveal scrolls just far enough to show an item, reporting whether it scrolled
+func (l *List) reveal(w Widget) bool {
+	i := indexOf(l.Items, w)
+	if i < 0 {
+		return false
+	}
+	scroll := l.scroll
+	if i < l.scroll {
+		l.scroll = i
+	} else if i >= l.scroll+l.Rows {
+		l.scroll = i - l.Rows + 1
+	}
+	return l.scroll != scroll
+}
+
+// Picture draws an image scaled to fit a square
+type Picture struct {
+	widget
+	Image   func(g *Game) *ebiten.Image
+	MaxSize float64
+
+	img *ebiten.Image
+}
+
+func (p *Picture) refresh(g *Game) bool {
+	img := p.Image(g)
+	changed := img != p.img
+	p.img = img
+	return changed
+}
+
+// scale fits the image inside MaxSize
+func (p *Picture) scale() float64 {
+	b := p.img.Bounds()
+	return p.MaxSize / math.Max(float64(b.Dx()), float64(b.Dy()))
+}
+
+func (p *Picture) measure() (float64, float64) {
+	if p.img == nil {
+		return 0, 0
+	}
+	b, s := p.img.Bounds(), p.scale()
+	return float64(b.Dx()) * s, float64(b.Dy()) * s
+}
+
+func (p *Picture) place(x, y, w, h float64) {
+	p.setBounds(x, y, w, h)
+}
+
+func (p *Picture) draw(screen *ebiten.Image, u *UI) {
+	if p.img == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(p.scale(), p.scale())
+	op.GeoM.Translate(p.bounds.x, p.bounds.y)
+	screen.DrawImage(p.img, op)
+}
+
+// --- Controls ---
+
+// Button runs OnPress when activated or when one of its shortcut keys is pressed
+type Button struct {
+	widget
+	Content Widget             // Usually a Label
+	Padding float64            // Space around the content
+	OnPress func(g *Game)      // Called when pressed
+	Keys    []ebiten.Key       // Shortcut keys that press the button from anywhere on its screen
+	Filled  bool               // Drawn with the theme's button color even when not focused
+	Active  func(g *Game) bool // Highlighted as taking input while this returns true
+
+	active bool
+}
+
+// textButton is a button labeled with a message
+func textButton(key string, scale float64, onPress func(g *Game), keys ...ebiten.Key) *Button {
+	return &Button{Content: &Label{Text: key, Scale: scale}, Padding: 6, OnPress: onPress, Keys: keys}
+}
+
+func (b *Button) children() []Widget { return []Widget{b.Content} }
+
+func (b *Button) refresh(g *Game) bool {
+	b.active = b.Active != nil && b.Active(g)
+	return false
+}
+
+func (b *Button) measure() (float64, float64) {
+	w, h := sizeOf(b.Content)
+	return w + b.Padding*2, h + b.Padding*2
+}
+
+func (b *Button) place(x, y, w, h float64) {
+	b.setBounds(x, y, w, h)
+	b.Content.place(x+b.Padding, y+b.Padding, w-b.Padding*2, h-b.Padding*2)
+}
+
+func (b *Button) draw(screen *ebiten.Image, u *UI) {
+	r := b.bounds
+	switch {
+	case b.active:
+		fillRect(screen, r.x, r.y, r.w, r.h, u.theme.Active)
+	case u.focus == b:
+		fillRect(screen, r.x, r.y, r.w, r.h, u.theme.Focus)
+	case b.Filled:
+		fillRect(screen, r.x, r.y, r.w, r.h, u.theme.Button)
+	}
+	b.Content.draw(screen, u)
+}
+
+func (b *Button) activate(g *Game) {
+	b.OnPress(g)
+}
+
+// rowPadding is the space above and below the text of list rows
+const rowPadding = 6
+
+// Choice is a named setting whose value Left/Right (or Enter and clicks) step through
+type Choice struct {
+	widget
+	Text       string                 // Message key of the name
+	Value      func(g *Game) string   // Current value, formatted for display
+	Adjust     func(g *Game, dir int) // Steps the value: dir is -1 or +1
+	Scale      float64
+	Indent     float64 // Shifts the name right, under the choice it belongs to
+	LabelWidth float64 // Width of the name column, so the values of a list line up
+
+	name  textCache
+	value string
+}
+
+func (c *Choice) refresh(g *Game) bool {
+	changed := c.name.update(c.Text, nil, g)
+	if v := "< " + c.Value(g) + " >"; v != c.value {
+		c.value = v
+		changed = true
+	}
+	return changed
+}
+
+func (c *Choice) measure() (float64, float64) {
+	w, h := textSize(c.value, c.Scale)
+	return c.LabelWidth + w + rowPadding*2, h + rowPadding*2
+}
+
+func (c *Choice) place(x, y, w, h float64) {
+	c.setBounds(x, y, w, h)
+}
+
+func (c *Choice) draw(screen *ebiten.Image, u *UI) {
+	r := c.bounds
+	if u.focus == c {
+		fillRect(screen, r.x, r.y, r.w, r.h, u.theme.Focus)
+	}
+	drawText(screen, c.name.text, r.x+rowPadding*3+c.Indent, r.y+rowPadding, c.Scale, u.theme.Text)
+	drawText(screen, c.value, r.x+c.LabelWidth, r.y+rowPadding, c.Scale, u.theme.Value)
+}
+
+func (c *Choice) activate(g *Game)        { c.Adjust(g, 1) }
+func (c *Choice) adjust(g *Game, dir int) { c.Adjust(g, dir) }
+
+// Slider sets a 0 to 1 level in even steps, with Left/Right or by clicking the bar
+type Slider struct {
+	widget
+	Text       string                 // Message key of the name
+	Level      func(g *Game) *float64 // The level it controls
+	Steps      int                    // Number of steps from 0 to 1
+	Preview    func(g *Game)          // Called after the level changes (nil for none)
+	Scale      float64
+	LabelWidth float64 // Width of the name column, as for Choice
+	BarWidth   float64
+
+	name  textCache
+	level float64
+}
+
+func (s *Slider) refresh(g *Game) bool {
+	s.level = *s.Level(g)
+	return s.name.update(s.Text, nil, g)
+}
+
+func (s *Slider) measure() (float64, float64) {
+	w, h := textSize(percent(1), s.Scale)
+	return s.LabelWidth + s.BarWidth + rowPadding*2 + w + rowPadding*2, h + rowPadding*2
+}
+
+func (s *Slider) place(x, y, w, h float64) {
+	s.setBounds(x, y, w, h)
+}
+
+// bar returns the clickable track of the slider
+func (s *Slider) bar() clickRegion {
+	r := s.bounds
+	return clickRegion{r.x + s.LabelWidth, r.y + rowPadding, s.BarWidth, r.h - rowPadding*2}
+}
+
+func (s *Slider) draw(screen *ebiten.Image, u *UI) {
+	r, bar := s.bounds, s.bar()
+	if u.focus == s {
+		fillRect(screen, r.x, r.y, r.w, r.h, u.theme.Focus)
+	}
+	drawText(screen, s.name.text, r.x+rowPadding*3, r.y+rowPadding, s.Scale, u.theme.Text)
+	fillRect(screen, bar.x, bar.y+bar.h/3, bar.w, bar.h/3, u.theme.Track)
+	fillRect(screen, bar.x, bar.y+bar.h/3, bar.w*s.level, bar.h/3, u.theme.Value)
+	fillRect(screen, bar.x+bar.w*s.level-2, bar.y, 4, bar.h, u.theme.Text)
+	drawText(screen, percent(s.level), bar.x+bar.w+rowPadding*2, r.y+rowPadding, s.Scale, u.theme.Value)
+}
+
+// set moves the level to a step and previews it
+func (s *Slider) set(g *Game, v float64) {
+	steps := float64(s.Steps)
+	*s.Level(g) = clamp01(math.Round(v*steps) / steps)
+	if s.Preview != nil {
+		s.Preview(g)
+	}
+}
+
+func (s *Slider) activate(g *Game) { s.adjust(g, 1) }
+
+func (s *Slider) adjust(g *Game, dir int) {
+	s.set(g, *s.Level(g)+float64(dir)/float64(s.Steps))
+}
+
+func (s *Slider) press(g *Game, x, y float64) {
+	bar := s.bar()
+	if !bar.contains(x, y) {
+		s.activate(g)
+		return
+	}
+	s.set(g, (x-bar.x)/bar.w)
+}
+
+// TextInput is a line the player types into; Enter submits it
+type TextInput struct {
+	widget
+	Text     string                       // Message key; its text is the fmt format applied to what was typed
+	MaxLen   int                          // Longest input in characters
+	Accept   func(r rune) (rune, bool)    // Maps typed characters, rejecting some (nil accepts all)
+	OnSubmit func(g *Game, in *TextInput) // Called on Enter or Confirm
+	Scale    float64
+
+	Value string // What has been typed
+	cache textCache
+}
+
+func (in *TextInput) refresh(g *Game) bool {
+	return in.cache.update(in.Text, func(*Game) interface{} { return in.Value }, g)
+}
+
+func (in *TextInput) measure() (float64, float64) {
+	return textSize(in.cache.text, in.Scale)
+}
+
+func (in *TextInput) place(x, y, w, h float64) {
+	in.setBounds(x, y, w, h)
+}
+
+func (in *TextInput) draw(screen *ebiten.Image, u *UI) {
+	drawText(screen, in.cache.text, in.bounds.x, in.bounds.y, in.Scale, u.theme.Text)
+}
+
+func (in *TextInput) activate(g *Game) {
+	in.OnSubmit(g, in)
+}
+
+// typeText adds this tick's typed characters and handles Backspace
+func (in *TextInput) typeText() {
+	runes := []rune(in.Value)
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+	}
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if in.Accept != nil {
+			var ok bool
+			if r, ok = in.Accept(r); !ok {
+				continue
+			}
+		}
+		if len(runes) < in.MaxLen {
+			runes = append(runes, r)
+		}
+	}
+	in.Value = string(runes)
+}
+
+// --- UI ---
+
+// UI is a screen built from a widget tree, centered on the view
+type UI struct {
+	Root     Widget
+	Overlay  color.Color   // Dims the game behind the screen (nil uses the theme's)
+	OnBack   func(g *Game) // Escape or Back (nil ignores them)
+	OnChange func(g *Game) // Called after a choice or slider changes a value
+
+	focus focusable // Widget that keyboard and gamepad input goes to
+	theme *Theme    // Theme of the current draw
+	width float64   // View width the tree was laid out for
+}
+
+// NewUI wraps a widget tree
+func NewUI(root Widget) *UI {
+	return &UI{Root: root, theme: defaultTheme}
+}
+
+// walk calls fn for every widget that isn't hidden, parents first
+func walk(w Widget, fn func(w Widget)) {
+	if w.base().hidden {
+		return
+	}
+	fn(w)
+	for _, c := range w.children() {
+		walk(c, fn)
+	}
+}
+
+// refresh updates what widgets show and lays the tree out again if anything moved
+func (u *UI) refresh(g *Game) {
+	changed := u.width != display.width
+	var update func(w Widget)
+	update = func(w Widget) {
+		b := w.base()
+		if hidden := b.Visible != nil && !b.Visible(g); hidden != b.hidden {
+			b.hidden = hidden
+			b.shown = false
+			changed = true
+		}
+		if b.hidden {
+			return
+		}
+		if r, ok := w.(refresher); ok && r.refresh(g) {
+			changed = true
+		}
+		for _, c := range w.children() {
+			update(c)
+		}
+	}
+	update(u.Root)
+	if changed {
+		u.layout()
+	}
+}
+
+// layout centers the tree on the view
+func (u *UI) layout() {
+	u.width = display.width
+	w, h := sizeOf(u.Root)
+	u.Root.place(math.Floor((display.width-w)/2), math.Floor((ScreenHeight-h)/2), w, h)
+}
+
+// focusables lists the widgets that can take focus, in tree order
+func (u *UI) focusables() []focusable {
+	var out []focusable
+	walk(u.Root, func(w Widget) {
+		if f, ok := w.(focusable); ok {
+			out = append(out, f)
+		}
+	})
+	return out
+}
+
+// FocusIndex is the position of the focused widget among the focusable ones
+func (u *UI) FocusIndex() int {
+	return max(indexOf(u.focusables(), u.focus), 0)
+}
+
+// setFocus focuses a widget, scrolling lists to show it, and reports whether focus moved
+func (u *UI) setFocus(f focusable) bool {
+	if f == u.focus {
+		return false
+	}
+	u.focus = f
+	scrolled := false
+	walk(u.Root, func(w Widget) {
+		if l, ok := w.(*List); ok && l.reveal(f) {
+			scrolled = true
+		}
+	})
+	if scrolled {
+		u.layout()
+	}
+	return true
+}
+
+// navPressed reports whether a navigation key, or its action, was pressed. While
+// typing, only the arrow keys and gamepad navigate, so bound letters can be typed.
+func (u *UI) navPressed(g *Game, a Action, key ebiten.Key, typing bool) bool {
+	if inpututil.IsKeyJustPressed(key) {
+		return true
+	}
+	if typing {
+		return g.input.gamepad.justPressed(a)
+	}
+	return g.input.JustPressed(a)
+}
+
+// Update moves focus and activates widgets from the keyboard, gamepad and pointer
+func (u *UI) Update(g *Game) {
+	u.refresh(g)
+	items := u.focusables()
+	if len(items) > 0 && indexOf(items, u.focus) < 0 {
+		u.setFocus(items[0])
+	}
+	_, typing := u.focus.(*TextInput)
+	moved := false
+
+	// Hovering focuses a widget and clicking activates it
+	for _, f := range items {
+		r := f.base().bounds
+		if !f.base().shown {
+			continue
+		}
+		if g.input.Hovered(r) {
+			moved = u.setFocus(f) || moved
+		}
+		if g.input.Tapped(r) {
+			u.setFocus(f)
+			if p, ok := f.(pointerTarget); ok {
+				x, y := g.input.Pointer()
+				p.press(g, x, y)
+			} else {
+				f.activate(g)
+			}
+			u.activated(g, f)
+			return
+		}
+	}
+
+	dir := 0
+	switch {
+	case len(items) > 0 && u.navPressed(g, ActionMoveUp, ebiten.KeyUp, typing):
+		moved = u.setFocus(items[(indexOf(items, u.focus)+len(items)-1)%len(items)])
+	case len(items) > 0 && u.navPressed(g, ActionMoveDown, ebiten.KeyDown, typing):
+		moved = u.setFocus(items[(indexOf(items, u.focus)+1)%len(items)])
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		dir = -1
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		dir = 1
+	case u.focus != nil && (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || (!typing && g.input.JustPressed(ActionConfirm)) || g.input.gamepad.justPressed(ActionConfirm)):
+		u.focus.activate(g)
+		u.activated(g, u.focus)
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || g.input.JustPressed(ActionBack):
+		if u.OnBack != nil {
+			u.OnBack(g)
+			return
+		}
+	}
+	if moved {
+		g.audio.Play(SoundMenu)
+	}
+	if a, ok := u.focus.(adjustable); ok && dir != 0 {
+		a.adjust(g, dir)
+		u.activated(g, u.focus)
+		return
+	}
+
+	if typing {
+		u.focus.(*TextInput).typeText()
+		return
+	}
+	// Shortcut keys press their buttons
+	for _, f := range items {
+		if b, ok := f.(*Button); ok {
+			for _, key := range b.Keys {
+				if inpututil.IsKeyJustPressed(key) {
+					b.activate(g)
+					u.activated(g, b)
+					return
+				}
+			}
+		}
+	}
+}
+
+// activated follows up on a widget being activated: buttons click, and
+// changed values are reported to OnChange
+func (u *UI) activated(g *Game, f focusable) {
+	switch f.(type) {
+	case *Button:
+		g.audio.Play(SoundMenu)
+	case adjustable:
+		if u.OnChange != nil {
+			u.OnChange(g)
+		}
+	}
+}
+
+// Draw dims the game and draws the screen over it
+func (u *UI) Draw(screen *ebiten.Image, g *Game) {
+	u.refresh(g)
+	u.theme = themeFor(g.settings)
+	overlay := u.Overlay
+	if overlay == nil {
+		overlay = u.theme.Overlay
+	}
+	fillRect(screen, 0, 0, display.width, ScreenHeight, overlay)
+	u.Root.draw(screen, u)
+}
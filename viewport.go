@@ -0,0 +1,145 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// --- Logical Resolution ---
+
+// The game draws to a canvas in logical pixels: always ScreenHeight tall, and
+// ScreenWidth wide or wider on ultrawide displays, up to MaxViewWidth (21:9).
+// The canvas is then scaled onto the window, with black bars filling any
+// space left over. Game logic and the HUD only ever see logical pixels.
+
+// MaxViewWidth is the widest the visible play area gets
+const MaxViewWidth = 1680
+
+// SpawnX is where kelp, coins and jellyfish enter the world: just past the
+// widest view with the camera zoomed all the way out, so they never pop into
+// sight and every window size plays the same run. Only the camera and
+// drawing depend on the actual view width.
+const SpawnX = MaxViewWidth / cameraZoomTop
+
+// ScaleMode is how the canvas is scaled up to the window
+type ScaleMode string
+
+const (
+	ScaleFit     ScaleMode = "fit"     // Largest scale that fits, smoothly filtered
+	ScaleInteger ScaleMode = "integer" // Largest whole-number scale that fits, for crisp pixels
+)
+
+// Anchor is the screen edge a HUD element or panel is positioned against.
+// Positions are authored for a ScreenWidth-wide screen; anchored elements
+// shift with the edge (or center) when the view is wider.
+type Anchor int
+
+const (
+	AnchorLeft Anchor = iota
+	AnchorCenter
+	AnchorRight
+)
+
+// viewport maps the logical canvas onto the window
+type viewport struct {
+	width            float64       // Logical width of the visible area (ScreenWidth to MaxViewWidth)
+	scale            float64       // Device pixels per logical pixel
+	offsetX, offsetY float64       // Top-left corner of the canvas on the screen (the letterbox bars' size)
+	filter           ebiten.Filter // Filter used to scale the canvas up
+	canvas           *ebiten.Image // Everything is drawn here first
+	world            *ebiten.Image // The world is drawn here, then through the camera onto the canvas
+}
+
+// display is the game window's viewport. There is a single window, and the
+// simulation needs the view width to know where the right edge is.
+var display = &viewport{width: ScreenWidth, scale: 1, filter: ebiten.FilterLinear}
+
+// layout fits the canvas into a screen of w x h device pixels
+func (v *viewport) layout(w, h int, mode ScaleMode) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	// Wider screens show more of the world; narrower ones keep ScreenWidth and get bars above and below
+	aspectWidth := math.Round(float64(ScreenHeight) * float64(w) / float64(h))
+	v.width = math.Max(ScreenWidth, math.Min(MaxViewWidth, aspectWidth))
+
+	v.scale = math.Min(float64(w)/v.width, float64(h)/ScreenHeight)
+	v.filter = ebiten.FilterLinear
+	if mode == ScaleInteger && v.scale >= 1 {
+		// Below 1x there's no whole-number scale that fits, so fall back to smooth scaling
+		v.scale = math.Floor(v.scale)
+		v.filter = ebiten.FilterNearest
+	}
+	v.offsetX = math.Floor((float64(w) - v.width*v.scale) / 2)
+	v.offsetY = math.Floor((float64(h) - ScreenHeight*v.scale) / 2)
+}
+
+// target returns the canvas to draw a frame on, resized if the view width changed
+func (v *viewport) target() *ebiten.Image {
+	v.canvas = v.fit(v.canvas, v.width)
+	v.canvas.Clear()
+	return v.canvas
+}
+
+// worldWidth is how much of the world the view can show: its width with the
+// camera zoomed all the way out
+func (v *viewport) worldWidth() float64 {
+	return math.Ceil(v.width / cameraZoomTop)
+}
+
+// worldTarget returns the image to draw the world on, resized with the view.
+// Drawing fills every pixel, so it isn't cleared.
+func (v *viewport) worldTarget() *ebiten.Image {
+	v.world = v.fit(v.world, v.worldWidth())
+	return v.world
+}
+
+// fit returns img, or a replacement if it's missing or not width wide
+func (v *viewport) fit(img *ebiten.Image, width float64) *ebiten.Image {
+	w := int(width)
+	if img != nil && img.Bounds().Dx() == w {
+		return img
+	}
+	if img != nil {
+		img.Deallocate()
+	}
+	return ebiten.NewImage(w, ScreenHeight)
+}
+
+// present scales the canvas onto the screen inside the letterbox bars
+func (v *viewport) present(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(v.scale, v.scale)
+	op.GeoM.Translate(v.offsetX, v.offsetY)
+	op.Filter = v.filter
+	screen.DrawImage(v.canvas, op)
+}
+
+// toLogical converts a screen position in device pixels to logical pixels
+func (v *viewport) toLogical(x, y float64) (float64, float64) {
+	return (x - v.offsetX) / v.scale, (y - v.offsetY) / v.scale
+}
+
+// anchorOffset is how far elements anchored to a are shifted from their
+// authored position
+func (v *viewport) anchorOffset(a Anchor) float64 {
+	switch a {
+	case AnchorCenter:
+		return (v.width - ScreenWidth) / 2
+	case AnchorRight:
+		return v.width - ScreenWidth
+	}
+	return 0
+}
+
+// Layout sizes the screen image to the window in device pixels, so the
+// canvas is scaled once, by the viewport, at full display resolution
+func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	s := ebiten.Monitor().DeviceScaleFactor()
+	w, h := int(float64(outsideWidth)*s), int(float64(outsideHeight)*s)
+	display.layout(w, h, g.settings.ScaleMode)
+	return w, h
+}